@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	clusterAutoscalerGenerator = "clusterAutoscalerGenerator"
+	// where cluster-autoscaler reports its status
+	autoscalerStatusNamespace = metav1.NamespaceSystem
+	autoscalerStatusConfigMap = "cluster-autoscaler-status"
+	nodeGroupMaxSize          = 10
+)
+
+var (
+	// node group names of the cloud providers, formatted with a random suffix
+	nodeGroupNames = []string{
+		"eks-ng-general-%s",
+		"gke-prod-default-pool-%s-grp",
+		"aks-nodepool1-%s-vmss",
+		"k8s-worker-asg-%s",
+	}
+)
+
+// nodeGroupName returns a random node group name
+func nodeGroupName() string {
+	return fmt.Sprintf(nodeGroupNames[randIntn(len(nodeGroupNames))], randString(8))
+}
+
+// ClusterAutoscalerGenerator create cluster-autoscaler events on pending mock pods, phantom or synthetic nodes
+// and the status config map of cluster-autoscaler
+type ClusterAutoscalerGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many scale ups and downs would be emitted
+	recorder  record.EventRecorder
+	mock      *MockOptions
+	nodes     *NodeGenerator
+}
+
+// Name returns the name of ClusterAutoscalerGenerator
+func (cg *ClusterAutoscalerGenerator) Name() string {
+	return clusterAutoscalerGenerator
+}
+
+// Generate create cluster-autoscaler events
+func (cg *ClusterAutoscalerGenerator) Generate() {
+	cg.initialize()
+	defer cg.finalize()
+}
+
+// Emit scale ups for pending mock pods and scale downs of nodes
+func (cg *ClusterAutoscalerGenerator) initialize() {
+	// the status config map belongs to cluster-autoscaler, it is never touched
+	status := phantomReference("ConfigMap", "v1", autoscalerStatusNamespace, autoscalerStatusConfigMap)
+	nodes := cg.scaledDownNodes()
+
+	for i := 0; i < cg.seed; i++ {
+		group := nodeGroupName()
+		size := 1 + randIntn(nodeGroupMaxSize/2)
+		newSize := size + 1 + randIntn(nodeGroupMaxSize-size)
+
+		if pod := cg.pendingPod(randString(15)); pod != nil {
+			switch i % 3 {
+			case 0:
+				cg.recorder.Eventf(pod, v1.EventTypeNormal, "TriggeredScaleUp", "pod triggered scale-up: [{%s %d->%d (max: %d)}]", group, size, newSize, nodeGroupMaxSize)
+				pace()
+				cg.recorder.Eventf(status, v1.EventTypeNormal, "ScaledUpGroup", "Scale-up: setting group %s size to %d instead of %d (max: %d)", group, newSize, size, nodeGroupMaxSize)
+			case 1:
+				cg.recorder.Eventf(pod, v1.EventTypeNormal, "NotTriggerScaleUp", "pod didn't trigger scale-up: %d max node group size reached, %d Insufficient cpu, 1 node(s) didn't match node selector", 1+randIntn(3), 1+randIntn(3))
+			default:
+				cg.recorder.Eventf(pod, v1.EventTypeNormal, "TriggeredScaleUp", "pod triggered scale-up: [{%s %d->%d (max: %d)}]", group, size, newSize, nodeGroupMaxSize)
+				pace()
+				cg.recorder.Eventf(pod, v1.EventTypeWarning, "FailedScaleUp", "Node scale up in zones us-central1-a associated with this pod failed: GCE quota exceeded. Pod is at risk of not being scheduled.")
+				pace()
+				cg.recorder.Eventf(status, v1.EventTypeWarning, "FailedToScaleUpGroup", "Scale-up failed for group %s: QUOTA_EXCEEDED: Quota 'CPUS' exceeded. Limit: 24.0 in region us-central1.", group)
+			}
+			pace()
+		}
+
+		if len(nodes) == 0 {
+			continue
+		}
+		node := &nodes[randIntn(len(nodes))]
+		if i%2 == 0 {
+			cg.recorder.Event(node, v1.EventTypeNormal, "ScaleDown", "marked the node as toBeDeleted/unschedulable")
+			pace()
+			cg.recorder.Eventf(status, v1.EventTypeNormal, "ScaleDownEmpty", "Scale-down: removing empty node %s", node.Name)
+			pace()
+			cg.recorder.Event(node, v1.EventTypeNormal, "ScaleDown", "node removed by cluster autoscaler")
+		} else {
+			cg.recorder.Event(node, v1.EventTypeNormal, "ScaleDown", "marked the node as toBeDeleted/unschedulable")
+			pace()
+			cg.recorder.Eventf(status, v1.EventTypeNormal, "ScaleDown", "Scale-down: node %s removed with drain", node.Name)
+			pace()
+			cg.recorder.Event(node, v1.EventTypeWarning, "ScaleDownFailed", "failed to drain the node, aborting ScaleDown")
+		}
+		pace()
+	}
+	fmt.Printf("Create %d cluster-autoscaler scale ups and downs successfully.\n", cg.seed)
+}
+
+// scaledDownNodes returns the nodes scale downs are emitted on. Scale downs of real nodes would
+// trigger cost alerts, they are emitted on the node generator's phantom or synthetic nodes only,
+// or on fictitious nodes if it targets real ones.
+func (cg *ClusterAutoscalerGenerator) scaledDownNodes() []v1.Node {
+	if cg.mock.Phantom || cg.mock.SyntheticNodes > 0 {
+		return cg.nodes.nodes()
+	}
+	return phantomNodeList(*phantomNodes).Items
+}
+
+// pendingPod returns a phantom or newly created pod named name that is never scheduled,
+// nil if it cannot be created
+func (cg *ClusterAutoscalerGenerator) pendingPod(name string) runtime.Object {
+	if cg.mock.Phantom {
+		return phantomReference("Pod", "v1", defaultNamespace, name)
+	}
+	pod := cg.mock.pod(name)
+	cg.mock.inertSpec(&pod.Spec)
+	created, err := cg.clientSet.CoreV1().Pods(defaultNamespace).Create(pod)
+	if err != nil {
+		fmt.Printf("Failed to create pending pod because of %v\n", err)
+		return nil
+	}
+	return created
+}
+
+// finalize all pending pods mocked
+func (cg *ClusterAutoscalerGenerator) finalize() {
+	if cg.mock.Phantom {
+		return
+	}
+	err := cg.clientSet.CoreV1().Pods(defaultNamespace).DeleteCollection(deleteOptions(), metav1.ListOptions{
+		LabelSelector: runSelector(cg.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to delete mock pending pods,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock pending pods successfully.\n")
+	}
+}
+
+// NewClusterAutoscalerGenerator return new cluster-autoscaler generator instance, it scales down the nodes of nodes
+func NewClusterAutoscalerGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions, nodes *NodeGenerator) *ClusterAutoscalerGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &ClusterAutoscalerGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+		nodes:     nodes,
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+	"k8s.io/kubernetes/pkg/kubelet/events"
+	"time"
+)
+
+const (
+	ownerChainGenerator = "ownerChainGenerator"
+)
+
+var (
+	enableOwnerChain   = flag.Bool("owner-chain", false, "emit events along Deployment -> ReplicaSet -> Pod owner reference chains")
+	ownerChainReplicas = flag.Int("owner-chain-replicas", 2, "pods in every owner chain")
+	ownerChainTimeout  = flag.Duration("owner-chain-timeout", time.Minute, "how long to wait for the controllers to create the replica set and pods of a chain")
+)
+
+var (
+	// pod events emitted at the bottom of a chain
+	chainPodEvents = []v1.Event{
+		v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  events.PullingImage,
+			Message: "pulling image",
+		},
+		v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  events.PulledImage,
+			Message: "Successfully pulled image",
+		},
+		v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  events.CreatedContainer,
+			Message: "Created container",
+		},
+		v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  events.StartedContainer,
+			Message: "Started container",
+		},
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  events.ContainerUnhealthy,
+			Message: "Readiness probe failed",
+		},
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  events.BackOffStartContainer,
+			Message: "Back-off restarting failed container",
+		},
+	}
+)
+
+// chainLink is one object of an owner chain
+type chainLink struct {
+	object runtime.Object
+	name   string
+}
+
+// ownerChain is a deployment owning a replica set owning pods
+type ownerChain struct {
+	deployment chainLink
+	replicaSet chainLink
+	pods       []chainLink
+}
+
+// Owner chain events generator
+type OwnerChainGenerator struct {
+	clientSet kubernetes.Interface
+	recorder  record.EventRecorder
+	seed      int // how many chains would be mocked
+	replicas  int
+	timeout   time.Duration
+	mock      *MockOptions
+}
+
+// Name returns the owner chain generator's name
+func (og *OwnerChainGenerator) Name() string {
+	return ownerChainGenerator
+}
+
+// Generate owner chain events
+func (og *OwnerChainGenerator) Generate() {
+	og.initialize()
+	defer og.finalize()
+}
+
+// initialize build owner chains and emit events at every level
+func (og *OwnerChainGenerator) initialize() {
+	for i := 0; i < og.seed; i++ {
+		name := randString(15)
+		var chain *ownerChain
+		var err error
+		if og.mock.Phantom {
+			chain = og.phantomChain(name)
+		} else {
+			chain, err = og.createChain(name)
+		}
+		if err != nil {
+			fmt.Printf("Failed to create owner chain,because of %v\n", err)
+			continue
+		}
+		og.emit(chain)
+	}
+	fmt.Printf("Create %d owner chains successfully.\n", og.seed)
+}
+
+// emit events on every level of chain with consistent names
+func (og *OwnerChainGenerator) emit(chain *ownerChain) {
+	og.recorder.Eventf(chain.deployment.object, v1.EventTypeNormal, "ScalingReplicaSet", "Scaled up replica set %s to %d", chain.replicaSet.name, len(chain.pods))
+	pace()
+	for _, pod := range chain.pods {
+		og.recorder.Eventf(chain.replicaSet.object, v1.EventTypeNormal, controller.SuccessfulCreatePodReason, "Created pod: %s", pod.name)
+		pace()
+	}
+	for _, pod := range chain.pods {
+		for _, event := range chainPodEvents {
+			og.recorder.Event(pod.object, event.Type, event.Reason, event.Message)
+			pace()
+		}
+	}
+}
+
+// phantomChain returns synthetic references named the way the controllers name them
+func (og *OwnerChainGenerator) phantomChain(name string) *ownerChain {
+	replicaSetName := fmt.Sprintf("%s-%s", name, randString(10))
+	chain := &ownerChain{
+		deployment: chainLink{phantomReference("Deployment", "apps/v1", defaultNamespace, name), name},
+		replicaSet: chainLink{phantomReference("ReplicaSet", "apps/v1", defaultNamespace, replicaSetName), replicaSetName},
+	}
+	for i := 0; i < og.replicas; i++ {
+		podName := fmt.Sprintf("%s-%s", replicaSetName, randString(5))
+		chain.pods = append(chain.pods, chainLink{phantomReference("Pod", "v1", defaultNamespace, podName), podName})
+	}
+	return chain
+}
+
+// createChain create a mock deployment and wait for its controllers to create the replica set and pods
+func (og *OwnerChainGenerator) createChain(name string) (*ownerChain, error) {
+	mockDeployment := og.mock.deployment(name)
+	mockDeployment.Spec.Replicas = int32Ptr(int32(og.replicas))
+	mockDeployment.Spec.Paused = false
+	deployment, err := og.clientSet.AppsV1().Deployments(defaultNamespace).Create(mockDeployment)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	options := metav1.ListOptions{LabelSelector: selector.String()}
+
+	var replicaSet *appsv1.ReplicaSet
+	var pods []v1.Pod
+	err = wait.PollImmediate(time.Second, og.timeout, func() (bool, error) {
+		replicaSetList, err := og.clientSet.AppsV1().ReplicaSets(defaultNamespace).List(options)
+		if err != nil {
+			return false, nil
+		}
+		for i := range replicaSetList.Items {
+			if metav1.IsControlledBy(&replicaSetList.Items[i], deployment) {
+				replicaSet = &replicaSetList.Items[i]
+			}
+		}
+		if replicaSet == nil {
+			return false, nil
+		}
+		podList, err := og.clientSet.CoreV1().Pods(defaultNamespace).List(options)
+		if err != nil {
+			return false, nil
+		}
+		pods = nil
+		for i := range podList.Items {
+			if metav1.IsControlledBy(&podList.Items[i], replicaSet) {
+				pods = append(pods, podList.Items[i])
+			}
+		}
+		return len(pods) >= og.replicas, nil
+	})
+	if replicaSet == nil {
+		return nil, fmt.Errorf("no replica set created for deployment %s: %v", name, err)
+	}
+	if err != nil {
+		fmt.Printf("Only %d of %d pods created for deployment %s,because of %v\n", len(pods), og.replicas, name, err)
+	}
+
+	chain := &ownerChain{
+		deployment: chainLink{deployment, deployment.Name},
+		replicaSet: chainLink{replicaSet, replicaSet.Name},
+	}
+	for i := range pods {
+		chain.pods = append(chain.pods, chainLink{&pods[i], pods[i].Name})
+	}
+	return chain, nil
+}
+
+// finalize remove the chains, the garbage collector removes replica sets and pods with their deployment
+func (og *OwnerChainGenerator) finalize() {
+	if og.mock.Phantom {
+		return
+	}
+	err := og.clientSet.AppsV1().Deployments(defaultNamespace).DeleteCollection(deleteOptions(), metav1.ListOptions{
+		LabelSelector: runSelector(og.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to delete owner chains,because of %v\n", err)
+	} else {
+		fmt.Print("Delete owner chains successfully.\n")
+	}
+}
+
+// NewOwnerChainGenerator return new owner chain generator instance
+func NewOwnerChainGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions) *OwnerChainGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	replicas := *ownerChainReplicas
+	if replicas <= 0 {
+		replicas = 1
+	}
+	return &OwnerChainGenerator{
+		clientSet: clientSet,
+		recorder:  recorder,
+		seed:      seed,
+		replicas:  replicas,
+		timeout:   *ownerChainTimeout,
+		mock:      mock,
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	cleanupCommand = "cleanup"
+)
+
+// mockKind lists and deletes the generator owned objects of one kind
+type mockKind struct {
+	name string
+	// list returns the objects matching options in all namespaces
+	list func(clientSet kubernetes.Interface, options metav1.ListOptions) ([]metav1.Object, error)
+	// delete removes one object
+	delete func(clientSet kubernetes.Interface, namespace, name string) error
+}
+
+// deletion order of the kinds of mock objects: owners go before what they own so controllers
+// do not recreate it, claims before their volumes, nodes after the pods on them and the lease
+// of the run last, once nothing else of the run is left
+var mockKindOrder = []string{
+	"horizontalpodautoscaler",
+	"cronjob",
+	"job",
+	"deployment",
+	"statefulset",
+	"daemonset",
+	"replicaset",
+	"replicationcontroller",
+	"pod",
+	"service",
+	"ingress",
+	"persistentvolumeclaim",
+	"persistentvolume",
+	"node",
+	"lease",
+}
+
+// kinds of mock objects generators create, in mockKindOrder whatever order they register in
+var mockKinds []mockKind
+
+// registerMockKind adds kind to mockKinds at its place in mockKindOrder, kinds missing from it go last
+func registerMockKind(kind mockKind) {
+	rank := func(name string) int {
+		for i, ordered := range mockKindOrder {
+			if ordered == name {
+				return i
+			}
+		}
+		return len(mockKindOrder)
+	}
+	i := 0
+	for i < len(mockKinds) && rank(mockKinds[i].name) <= rank(kind.name) {
+		i++
+	}
+	mockKinds = append(mockKinds, mockKind{})
+	copy(mockKinds[i+1:], mockKinds[i:])
+	mockKinds[i] = kind
+}
+
+// registerMock registers the kind name from the typed list, in all namespaces, and delete calls of its client
+func registerMock(name string, list func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error), remove func(clientSet kubernetes.Interface, namespace, name string) error) {
+	registerMockKind(mockKind{
+		name: name,
+		list: func(clientSet kubernetes.Interface, options metav1.ListOptions) ([]metav1.Object, error) {
+			objectList, err := list(clientSet, options)
+			if err != nil {
+				return nil, err
+			}
+			items, err := meta.ExtractList(objectList)
+			if err != nil {
+				return nil, err
+			}
+			objects := make([]metav1.Object, 0, len(items))
+			for _, item := range items {
+				object, err := meta.Accessor(item)
+				if err != nil {
+					return nil, err
+				}
+				objects = append(objects, object)
+			}
+			return objects, nil
+		},
+		delete: remove,
+	})
+}
+
+// mockKindNamed returns the registered kind named name, nil if there is none
+func mockKindNamed(name string) *mockKind {
+	for i := range mockKinds {
+		if mockKinds[i].name == name {
+			return &mockKinds[i]
+		}
+	}
+	return nil
+}
+
+// deleteOptions let the garbage collector remove dependents of deleted mocks in background
+func deleteOptions() *metav1.DeleteOptions {
+	background := metav1.DeletePropagationBackground
+	return &metav1.DeleteOptions{PropagationPolicy: &background}
+}
+
+// generatorSelector selects all generator owned objects
+func generatorSelector() string {
+	return fmt.Sprintf("%s=%s", generatorLabel, kubernetesEventsGenerator)
+}
+
+// runSelector selects the objects owned by run
+func runSelector(runID string) string {
+	return fmt.Sprintf("%s,%s=%s", generatorSelector(), runIDLabel, runID)
+}
+
+// deleteMocks removes every mock object matching selector and reports what was removed
+func deleteMocks(clientSet kubernetes.Interface, selector string) {
+	for _, kind := range mockKinds {
+		objects, err := kind.list(clientSet, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			fmt.Printf("Failed to list mock %s,because of %v\n", kind.name, err)
+			continue
+		}
+		for _, object := range objects {
+			if err := kind.delete(clientSet, object.GetNamespace(), object.GetName()); err != nil {
+				fmt.Printf("Failed to delete mock %s %s/%s,because of %v\n", kind.name, object.GetNamespace(), object.GetName(), err)
+				continue
+			}
+			fmt.Printf("Delete mock %s %s/%s successfully.\n", kind.name, object.GetNamespace(), object.GetName())
+		}
+	}
+}
+
+// runCleanup implements the cleanup command
+func runCleanup(clientSet kubernetes.Interface, args []string) {
+	flags := flag.NewFlagSet(cleanupCommand, flag.ExitOnError)
+	all := flags.Bool("all", false, "remove every generator owned object and event in the cluster")
+	runID := flags.String("run-id", "", "remove the objects and events of one run")
+	flags.Parse(args)
+
+	switch {
+	case *all:
+		deleteMocks(clientSet, generatorSelector())
+		deleteEvents(clientSet, "", nil)
+	case *runID != "":
+		// the uids of the objects match the aggregated events of the run, collect them before the objects go
+		uids := mockUIDs(clientSet, runSelector(*runID))
+		deleteMocks(clientSet, runSelector(*runID))
+		deleteEvents(clientSet, *runID, uids)
+	default:
+		fmt.Printf("Usage: %s [--all | --run-id=<run id>]\n", cleanupCommand)
+		flags.PrintDefaults()
+	}
+}
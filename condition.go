@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	patchNodeConditions = flag.Bool("patch-node-conditions", false, "patch the node problem detector condition matching a problem or recovery event into the status of synthetic nodes")
+)
+
+// nodeConditionChange is the node condition a node problem detector event goes with
+type nodeConditionChange struct {
+	conditionType v1.NodeConditionType
+	status        v1.ConditionStatus
+}
+
+// node events and the conditions node problem detector sets along with them,
+// problems set the condition and recoveries restore it
+var nodeConditionChanges = map[string]nodeConditionChange{
+	"DockerHung":               {"KernelDeadlock", v1.ConditionTrue},
+	"AUFSUmountHung":           {"KernelDeadlock", v1.ConditionTrue},
+	"KernelHasNoDeadlock":      {"KernelDeadlock", v1.ConditionFalse},
+	"FilesystemIsReadOnly":     {"ReadonlyFilesystem", v1.ConditionTrue},
+	"FilesystemIsNotReadOnly":  {"ReadonlyFilesystem", v1.ConditionFalse},
+	"NodeHasFDPressure":        {"FDPressure", v1.ConditionTrue},
+	"NodeHasNoFDPressure":      {"FDPressure", v1.ConditionFalse},
+	"NTPIsDown":                {"NTPProblem", v1.ConditionTrue},
+	"NTPIsUp":                  {"NTPProblem", v1.ConditionFalse},
+	"NoCorruptDockerOverlay2":  {"CorruptDockerOverlay2", v1.ConditionFalse},
+	"NoFrequentKubeletRestart": {"FrequentKubeletRestart", v1.ConditionFalse},
+	"NoFrequentDockerRestart":  {"FrequentDockerRestart", v1.ConditionFalse},
+}
+
+// patchNodeCondition sets the condition going with event in the status of node, if there is one.
+// node is updated to the patched copy so transitions are tracked across events.
+func (ng *NodeGenerator) patchNodeCondition(node *v1.Node, reason, message string) {
+	change, ok := nodeConditionChanges[reason]
+	if !ok {
+		return
+	}
+
+	now := metav1.Now()
+	transition := now
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == change.conditionType && condition.Status == change.status {
+			transition = condition.LastTransitionTime
+		}
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []v1.NodeCondition{
+				{
+					Type:               change.conditionType,
+					Status:             change.status,
+					LastHeartbeatTime:  now,
+					LastTransitionTime: transition,
+					Reason:             reason,
+					Message:            message,
+				},
+			},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Failed to build condition patch of node %s,because of %v\n", node.Name, err)
+		return
+	}
+	patched, err := ng.clientSet.CoreV1().Nodes().PatchStatus(node.Name, patch)
+	if err != nil {
+		fmt.Printf("Failed to patch condition %s of node %s,because of %v\n", change.conditionType, node.Name, err)
+		return
+	}
+	*node = *patched
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"time"
+)
+
+const (
+	cronJobGenerator = "cronJobGenerator"
+	// schedule of mock cron jobs, they are suspended so it never fires
+	cronJobSchedule = "*/5 * * * *"
+	cronJobInterval = 5 * time.Minute
+	// scheduled times are drawn from the schedules of the day before the run started
+	cronJobSchedules = 24 * int(time.Hour/cronJobInterval)
+)
+
+var (
+	// start of the run truncated to the hour, scheduled times stay the same all run long
+	runStart = time.Now().Truncate(time.Hour)
+)
+
+func init() {
+	registerMock("cronjob",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.BatchV1beta1().CronJobs(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.BatchV1beta1().CronJobs(namespace).Delete(name, deleteOptions())
+		})
+}
+
+// cronJobEvents returns the cron job controller's events of cron job name: a few scheduled
+// jobs created and completed, a missed schedule, a job it did not create and a start it
+// cannot decide on
+func cronJobEvents(name string) []v1.Event {
+	// the controller names jobs after their scheduled time in minutes. The time is a seeded offset
+	// before the run start, so runs with the same seed started in the same hour emit the same messages.
+	scheduled := runStart.Add(-time.Duration(randIntn(cronJobSchedules)) * cronJobInterval)
+	jobName := func(t time.Time) string {
+		return fmt.Sprintf("%s-%d", name, t.Unix()/60)
+	}
+
+	events := []v1.Event{}
+	for i := 2; i > 0; i-- {
+		t := scheduled.Add(-time.Duration(i) * cronJobInterval)
+		events = append(events,
+			v1.Event{
+				Type:    v1.EventTypeNormal,
+				Reason:  "SuccessfulCreate",
+				Message: fmt.Sprintf("Created job %s", jobName(t)),
+			},
+			v1.Event{
+				Type:    v1.EventTypeNormal,
+				Reason:  "SawCompletedJob",
+				Message: fmt.Sprintf("Saw completed job: %s", jobName(t)),
+			},
+		)
+	}
+	return append(events,
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  "MissSchedule",
+			Message: fmt.Sprintf("Missed scheduled time to start a job: %s", scheduled.Format(time.RFC1123Z)),
+		},
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  "UnexpectedJob",
+			Message: fmt.Sprintf("Saw a job that the controller did not create or forgot: %s-%s", name, randString(5)),
+		},
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  "FailedNeedsStart",
+			Message: "Cannot determine if job needs to be started: too many missed start time (> 100). Set or decrease .spec.startingDeadlineSeconds or check clock skew.",
+		},
+	)
+}
+
+// CronJobGenerator create events on mock cron jobs
+type CronJobGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many cron jobs would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+}
+
+// Name returns the name of CronJobGenerator
+func (cg *CronJobGenerator) Name() string {
+	return cronJobGenerator
+}
+
+// Generate create events on mock cron jobs
+func (cg *CronJobGenerator) Generate() {
+	cg.initialize()
+	defer cg.finalize()
+}
+
+// Mock and create several cron jobs
+func (cg *CronJobGenerator) initialize() {
+	for i := 0; i < cg.seed; i++ {
+		mockCronJobName := randString(15)
+		var cronJob runtime.Object
+		if cg.mock.Phantom {
+			cronJob = phantomReference("CronJob", "batch/v1beta1", defaultNamespace, mockCronJobName)
+		} else {
+			created, err := cg.clientSet.BatchV1beta1().CronJobs(defaultNamespace).Create(cg.mock.cronJob(mockCronJobName))
+			if err != nil {
+				fmt.Printf("Failed to create cron job because of %v\n", err)
+				continue
+			}
+			cronJob = created
+		}
+
+		for _, e := range cronJobEvents(mockCronJobName) {
+			cg.recorder.Event(cronJob, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create %d cron jobs successfully.\n", cg.seed)
+}
+
+// finalize all cron jobs mocked
+func (cg *CronJobGenerator) finalize() {
+	if cg.mock.Phantom {
+		return
+	}
+	err := cg.clientSet.BatchV1beta1().CronJobs(defaultNamespace).DeleteCollection(deleteOptions(), metav1.ListOptions{
+		LabelSelector: runSelector(cg.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to delete mock cron jobs,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock cron jobs successfully.\n")
+	}
+}
+
+// NewCronJobGenerator return new cron job generator instance
+func NewCronJobGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions) *CronJobGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &CronJobGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+	}
+}
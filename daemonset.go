@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+)
+
+const (
+	daemonSetGenerator = "daemonSetGenerator"
+
+	// reasons of the daemon set controller, pkg/controller/daemon is not vendored
+	selectingAllReason    = "SelectingAll"
+	failedPlacementReason = "FailedPlacement"
+	failedDaemonPodReason = "FailedDaemonPod"
+)
+
+func init() {
+	registerMock("daemonset",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.AppsV1().DaemonSets(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.AppsV1().DaemonSets(namespace).Delete(name, deleteOptions())
+		})
+}
+
+// daemonSetEvents returns the events the daemon set controller emits for daemon set name
+// running on nodes: a pod created per node, a node the pod does not fit on, a failed pod
+// being replaced and a rejected pod creation
+func daemonSetEvents(name string, nodes []v1.Node) []v1.Event {
+	podName := func() string {
+		return fmt.Sprintf("%s-%s", name, randString(5))
+	}
+
+	events := []v1.Event{}
+	for range nodes {
+		events = append(events, v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  controller.SuccessfulCreatePodReason,
+			Message: fmt.Sprintf("Created pod: %s", podName()),
+		})
+	}
+	if len(nodes) > 0 {
+		full := nodes[randIntn(len(nodes))].Name
+		broken := nodes[randIntn(len(nodes))].Name
+		failed := podName()
+		events = append(events,
+			v1.Event{
+				Type:    v1.EventTypeWarning,
+				Reason:  failedPlacementReason,
+				Message: fmt.Sprintf("failed to place pod on %q: Node didn't have enough resource: cpu, requested: 250, used: 3900, capacity: 4000", full),
+			},
+			v1.Event{
+				Type:    v1.EventTypeWarning,
+				Reason:  failedDaemonPodReason,
+				Message: fmt.Sprintf("Found failed daemon pod %s/%s on node %s, will try to kill it", defaultNamespace, failed, broken),
+			},
+			v1.Event{
+				Type:    v1.EventTypeNormal,
+				Reason:  controller.SuccessfulDeletePodReason,
+				Message: fmt.Sprintf("Deleted pod: %s", failed),
+			},
+			v1.Event{
+				Type:    v1.EventTypeNormal,
+				Reason:  controller.SuccessfulCreatePodReason,
+				Message: fmt.Sprintf("Created pod: %s", podName()),
+			},
+		)
+	}
+	return append(events,
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  controller.FailedCreatePodReason,
+			Message: fmt.Sprintf("Error creating: pods %q is forbidden: exceeded quota: compute-resources, requested: limits.cpu=250m, used: limits.cpu=2, limited: limits.cpu=2", podName()),
+		},
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  selectingAllReason,
+			Message: "This daemon set is selecting all pods. A non-empty selector is required.",
+		},
+	)
+}
+
+// DaemonSetGenerator create events on mock daemon sets, they refer to the nodes of the node generator
+type DaemonSetGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many daemon sets would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+	nodes     *NodeGenerator
+}
+
+// Name returns the name of DaemonSetGenerator
+func (dg *DaemonSetGenerator) Name() string {
+	return daemonSetGenerator
+}
+
+// Generate create events on mock daemon sets
+func (dg *DaemonSetGenerator) Generate() {
+	dg.initialize()
+	defer dg.finalize()
+}
+
+// Mock and create several daemon sets
+func (dg *DaemonSetGenerator) initialize() {
+	nodes := dg.nodes.nodes()
+	if len(nodes) == 0 {
+		fmt.Print("No nodes to place daemon pods on, only node independent daemon set events are emitted.\n")
+	}
+
+	for i := 0; i < dg.seed; i++ {
+		mockDaemonSetName := randString(15)
+		var daemonSet runtime.Object
+		if dg.mock.Phantom {
+			daemonSet = phantomReference("DaemonSet", "apps/v1", defaultNamespace, mockDaemonSetName)
+		} else {
+			created, err := dg.clientSet.AppsV1().DaemonSets(defaultNamespace).Create(dg.mock.daemonSet(mockDaemonSetName))
+			if err != nil {
+				fmt.Printf("Failed to create daemon set because of %v\n", err)
+				continue
+			}
+			daemonSet = created
+		}
+
+		for _, e := range daemonSetEvents(mockDaemonSetName, nodes) {
+			dg.recorder.Event(daemonSet, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create %d daemon sets successfully.\n", dg.seed)
+}
+
+// finalize all daemon sets mocked
+func (dg *DaemonSetGenerator) finalize() {
+	if dg.mock.Phantom {
+		return
+	}
+	err := dg.clientSet.AppsV1().DaemonSets(defaultNamespace).DeleteCollection(deleteOptions(), metav1.ListOptions{
+		LabelSelector: runSelector(dg.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to delete mock daemon sets,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock daemon sets successfully.\n")
+	}
+}
+
+// NewDaemonSetGenerator return new daemon set generator instance, its events name the nodes of nodes
+func NewDaemonSetGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions, nodes *NodeGenerator) *DaemonSetGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &DaemonSetGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+		nodes:     nodes,
+	}
+}
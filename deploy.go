@@ -5,11 +5,10 @@ import (
 	"k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/kubernetes/pkg/controller/deployment/util"
-	"time"
 )
 
 const (
@@ -87,11 +86,23 @@ var (
 	}
 )
 
+func init() {
+	registerMock("deployment",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.AppsV1().Deployments(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.AppsV1().Deployments(namespace).Delete(name, deleteOptions())
+		})
+}
+
 // DeploymentGenerator create
 type DeploymentGenerator struct {
 	clientSet kubernetes.Interface
 	seed      int // how many deployment would be mocked
 	recorder  record.EventRecorder
+	mock      *MockOptions
+	targets   *Targets // existing deployments to emit on instead of mocks, if any
 }
 
 // Name() returns the name of DeploymentGenerator
@@ -108,65 +119,57 @@ func (dg *DeploymentGenerator) Generate() {
 
 // Mock and create several deployments
 func (dg *DeploymentGenerator) initialize() {
+	if dg.targets != nil {
+		dg.initializeTargets()
+		return
+	}
 	for i := 0; i < dg.seed; i++ {
-		mockDeploymentName := rand.String(15)
-		deployment, err := dg.clientSet.AppsV1().Deployments(defaultNamespace).Create(&v1.Deployment{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: mockDeploymentName,
-				Labels: map[string]string{
-					"gen-by": kubernetesEventsGenerator,
-				},
-			},
-			Spec: v1.DeploymentSpec{
-				Replicas: int32Ptr(2),
-				Selector: &metav1.LabelSelector{
-					MatchLabels: map[string]string{
-						"app": mockDeploymentName,
-					},
-				},
-				Template: corev1.PodTemplateSpec{
-					ObjectMeta: metav1.ObjectMeta{
-						Labels: map[string]string{
-							"app": mockDeploymentName,
-						},
-					},
-					Spec: corev1.PodSpec{
-						Containers: []corev1.Container{
-							{
-								Name:  "web",
-								Image: "nginx:1.12",
-								Ports: []corev1.ContainerPort{
-									{
-										Name:          "http",
-										Protocol:      corev1.ProtocolTCP,
-										ContainerPort: 80,
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		})
-
-		if err != nil {
-			fmt.Printf("Failed to create deployment because of %v\n", err)
-			continue
+		mockDeploymentName := randString(15)
+		var deployment runtime.Object
+		if dg.mock.Phantom {
+			deployment = phantomReference("Deployment", "apps/v1", defaultNamespace, mockDeploymentName)
+		} else {
+			created, err := dg.createDeployment(mockDeploymentName)
+			if err != nil {
+				fmt.Printf("Failed to create deployment because of %v\n", err)
+				continue
+			}
+			deployment = created
 		}
 
 		for _, e := range deploymentEvents {
 			dg.recorder.Event(deployment, e.Type, e.Reason, e.Message)
-			time.Sleep(3 * time.Second)
+			pace()
 		}
 
 	}
 	fmt.Printf("Create %d deployments successfully.\n", dg.seed)
 }
 
+// initializeTargets emit deployment events on the targeted existing deployments, they are never modified
+func (dg *DeploymentGenerator) initializeTargets() {
+	deployments := dg.targets.Deployments()
+	for _, deployment := range deployments {
+		for _, e := range deploymentEvents {
+			dg.recorder.Event(deployment, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create events on %d existing deployments successfully.\n", len(deployments))
+}
+
+// createDeployment create a mock deployment named name
+func (dg *DeploymentGenerator) createDeployment(name string) (*v1.Deployment, error) {
+	return dg.clientSet.AppsV1().Deployments(defaultNamespace).Create(dg.mock.deployment(name))
+}
+
 // finalize all deployments mocked
 func (dg *DeploymentGenerator) finalize() {
-	err := dg.clientSet.AppsV1().Deployments(defaultNamespace).DeleteCollection(nil, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("gen-by=%s", kubernetesEventsGenerator),
+	if dg.mock.Phantom || dg.targets != nil {
+		return
+	}
+	err := dg.clientSet.AppsV1().Deployments(defaultNamespace).DeleteCollection(deleteOptions(), metav1.ListOptions{
+		LabelSelector: runSelector(dg.mock.RunID),
 	})
 	if err != nil {
 		fmt.Printf("Failed to delete mock deployments,because of %v\n", err)
@@ -176,7 +179,7 @@ func (dg *DeploymentGenerator) finalize() {
 }
 
 // initialize generator and create mock deployment
-func NewDeploymentGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int) Generator {
+func NewDeploymentGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions, targets *Targets) Generator {
 
 	// ensure the event amount to minSeed
 	if seed <= minSeed {
@@ -187,6 +190,8 @@ func NewDeploymentGenerator(clientSet kubernetes.Interface, recorder record.Even
 		clientSet: clientSet,
 		seed:      seed,
 		recorder:  recorder,
+		mock:      mock,
+		targets:   targets,
 	}
 
 	return g
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
+	"sync"
+	"time"
+)
+
+const (
+	runIDAnnotation = "kubernetes-events-generator/run-id"
+	// the recorder's spam filter lets this many events per involved object through,
+	// then one every 5 minutes
+	maxEventsPerObject = 25
+	// the broadcaster writes events asynchronously, give it this long to write the last ones of a pass
+	eventsDrainDelay = 5 * time.Second
+)
+
+var (
+	cleanupEvents = flag.Bool("cleanup-events", false, "delete the events of this run after every generation pass")
+)
+
+// runRecorder annotates every event with the run id, so the events of a run can be found again,
+// and remembers the uids of the objects it recorded events on, the correlator drops the annotation
+// of the events it aggregates
+type runRecorder struct {
+	record.EventRecorder
+	runID string
+
+	lock     sync.Mutex
+	involved map[types.UID]bool
+}
+
+// involve remembers the uid of object
+func (rr *runRecorder) involve(object runtime.Object) {
+	ref, err := reference.GetReference(scheme.Scheme, object)
+	if err != nil || ref.UID == "" {
+		return
+	}
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	rr.involved[ref.UID] = true
+}
+
+// takeInvolved returns the uids of the objects involved since the last call
+func (rr *runRecorder) takeInvolved() map[types.UID]bool {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	involved := rr.involved
+	rr.involved = make(map[types.UID]bool)
+	return involved
+}
+
+func (rr *runRecorder) annotations(annotations map[string]string) map[string]string {
+	merged := map[string]string{
+		runIDAnnotation: rr.runID,
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return merged
+}
+
+func (rr *runRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	rr.involve(object)
+	rr.EventRecorder.AnnotatedEventf(object, rr.annotations(nil), eventtype, reason, "%s", message)
+}
+
+func (rr *runRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	rr.involve(object)
+	rr.EventRecorder.AnnotatedEventf(object, rr.annotations(nil), eventtype, reason, messageFmt, args...)
+}
+
+func (rr *runRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	rr.involve(object)
+	rr.EventRecorder.AnnotatedEventf(object, rr.annotations(annotations), eventtype, reason, messageFmt, args...)
+}
+
+// NewRunRecorder return a recorder annotating the events of recorder with runID
+func NewRunRecorder(recorder record.EventRecorder, runID string) *runRecorder {
+	return &runRecorder{
+		EventRecorder: recorder,
+		runID:         runID,
+		involved:      make(map[types.UID]bool),
+	}
+}
+
+// mockUIDs returns the uids of the mock objects matching selector
+func mockUIDs(clientSet kubernetes.Interface, selector string) map[types.UID]bool {
+	uids := make(map[types.UID]bool)
+	for _, kind := range mockKinds {
+		objects, err := kind.list(clientSet, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			fmt.Printf("Failed to list mock %s,because of %v\n", kind.name, err)
+			continue
+		}
+		for _, object := range objects {
+			uids[object.GetUID()] = true
+		}
+	}
+	return uids
+}
+
+// deleteEvents removes the events recorded by the generator in all namespaces, only the events
+// of runID unless it is empty. Aggregated events lost the run id, they are matched on the uids
+// of the objects of the run instead
+func deleteEvents(clientSet kubernetes.Interface, runID string, uids map[types.UID]bool) {
+	eventList, err := clientSet.CoreV1().Events(metav1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("source", kubernetesEventsGenerator).String(),
+	})
+	if err != nil {
+		fmt.Printf("Failed to list generated events,because of %v\n", err)
+		return
+	}
+
+	removed := 0
+	for _, event := range eventList.Items {
+		if runID != "" && event.Annotations[runIDAnnotation] != runID {
+			if _, annotated := event.Annotations[runIDAnnotation]; annotated || !uids[event.InvolvedObject.UID] {
+				continue
+			}
+		}
+		err := clientSet.CoreV1().Events(event.Namespace).Delete(event.Name, &metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			fmt.Printf("Failed to delete event %s/%s,because of %v\n", event.Namespace, event.Name, err)
+			continue
+		}
+		removed++
+	}
+	fmt.Printf("Delete %d generated events successfully.\n", removed)
+}
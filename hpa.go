@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	hpaGenerator = "hpaGenerator"
+
+	hpaMinReplicas       = 1
+	hpaMaxReplicas       = 10
+	hpaTargetUtilization = 80
+)
+
+func init() {
+	registerMock("horizontalpodautoscaler",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.AutoscalingV1().HorizontalPodAutoscalers(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.AutoscalingV1().HorizontalPodAutoscalers(namespace).Delete(name, deleteOptions())
+		})
+}
+
+// desiredReplicas returns the replicas the autoscaler computes for current replicas at utilization percent
+func desiredReplicas(current, utilization int) int {
+	desired := (current*utilization + hpaTargetUtilization - 1) / hpaTargetUtilization
+	if desired > hpaMaxReplicas {
+		desired = hpaMaxReplicas
+	}
+	if desired < hpaMinReplicas {
+		desired = hpaMinReplicas
+	}
+	return desired
+}
+
+// hpaEvents returns the events of autoscaler name scaling deployment target: metrics missing at first,
+// then a scale up on high cpu utilization, a status update conflict and a scale down
+func hpaEvents(name, target string) []v1.Event {
+	current := hpaMinReplicas + randIntn(hpaMaxReplicas/2)
+	high := hpaTargetUtilization + 10 + randIntn(150)
+	scaledUp := desiredReplicas(current, high)
+	low := 10 + randIntn(hpaTargetUtilization/2)
+	scaledDown := desiredReplicas(scaledUp, low)
+	metricsError := "unable to get metrics for resource cpu: no metrics returned from resource metrics API"
+
+	return []v1.Event{
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "FailedGetScale",
+			Message: fmt.Sprintf("deployments/scale.apps %q not found", target),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "FailedGetResourceMetric",
+			Message: metricsError,
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "FailedComputeMetricsReplicas",
+			Message: fmt.Sprintf("failed to get cpu utilization: %s", metricsError),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "FailedGetResourceMetric",
+			Message: fmt.Sprintf("did not receive metrics for any ready pods (%d replicas, 0 ready)", current),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "SuccessfulRescale",
+			Message: fmt.Sprintf("New size: %d; reason: cpu resource utilization (percentage of request) above target (current replicas: %d, utilization: %d%%, target: %d%%)", scaledUp, current, high, hpaTargetUtilization),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "FailedUpdateStatus",
+			Message: fmt.Sprintf("Operation cannot be fulfilled on horizontalpodautoscalers.autoscaling %q: the object has been modified; please apply your changes to the latest version and try again", name),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "SuccessfulRescale",
+			Message: fmt.Sprintf("New size: %d; reason: All metrics below target (current replicas: %d, utilization: %d%%, target: %d%%)", scaledDown, scaledUp, low, hpaTargetUtilization),
+		},
+	}
+}
+
+// HPAGenerator create events on mock horizontal pod autoscalers of mock deployments
+type HPAGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many autoscalers would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+	targets   []string // deployments scaled by the autoscalers of the pass
+}
+
+// Name returns the name of HPAGenerator
+func (hg *HPAGenerator) Name() string {
+	return hpaGenerator
+}
+
+// Generate create events on mock horizontal pod autoscalers
+func (hg *HPAGenerator) Generate() {
+	hg.initialize()
+	defer hg.finalize()
+}
+
+// Mock and create several autoscalers, each with the deployment it scales
+func (hg *HPAGenerator) initialize() {
+	hg.targets = nil
+	for i := 0; i < hg.seed; i++ {
+		mockHPAName := randString(15)
+		mockDeploymentName := randString(15)
+		var hpa runtime.Object
+		if hg.mock.Phantom {
+			hpa = phantomReference("HorizontalPodAutoscaler", "autoscaling/v1", defaultNamespace, mockHPAName)
+		} else {
+			created, err := hg.createHPA(mockHPAName, mockDeploymentName)
+			if err != nil {
+				fmt.Printf("Failed to create horizontal pod autoscaler because of %v\n", err)
+				continue
+			}
+			hpa = created
+		}
+
+		for _, e := range hpaEvents(mockHPAName, mockDeploymentName) {
+			hg.recorder.Event(hpa, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create %d horizontal pod autoscalers successfully.\n", hg.seed)
+}
+
+// createHPA create the autoscaler name of a new deployment target. The deployment has no replicas,
+// the autoscaler controller does not scale targets scaled to zero.
+func (hg *HPAGenerator) createHPA(name, target string) (runtime.Object, error) {
+	deployment := hg.mock.deployment(target)
+	deployment.Spec.Replicas = int32Ptr(0)
+	if _, err := hg.clientSet.AppsV1().Deployments(defaultNamespace).Create(deployment); err != nil {
+		return nil, err
+	}
+	hg.targets = append(hg.targets, target)
+	return hg.clientSet.AutoscalingV1().HorizontalPodAutoscalers(defaultNamespace).Create(hg.mock.horizontalPodAutoscaler(name, target))
+}
+
+// finalize all autoscalers and their deployments mocked
+func (hg *HPAGenerator) finalize() {
+	if hg.mock.Phantom {
+		return
+	}
+	options := metav1.ListOptions{
+		LabelSelector: runSelector(hg.mock.RunID),
+	}
+	if err := hg.clientSet.AutoscalingV1().HorizontalPodAutoscalers(defaultNamespace).DeleteCollection(deleteOptions(), options); err != nil {
+		fmt.Printf("Failed to delete mock horizontal pod autoscalers,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock horizontal pod autoscalers successfully.\n")
+	}
+	// only the scaled deployments, other mock deployments of the run are not ours to delete
+	for _, target := range hg.targets {
+		if err := hg.clientSet.AppsV1().Deployments(defaultNamespace).Delete(target, deleteOptions()); err != nil {
+			fmt.Printf("Failed to delete mock deployment %s,because of %v\n", target, err)
+			continue
+		}
+		fmt.Printf("Delete mock deployment %s successfully.\n", target)
+	}
+	hg.targets = nil
+}
+
+// NewHPAGenerator return new horizontal pod autoscaler generator instance
+func NewHPAGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions) *HPAGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &HPAGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+	}
+}
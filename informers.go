@@ -0,0 +1,25 @@
+package main
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+)
+
+// startInformer runs an informer on resource of client for the lifetime of the process,
+// it only caches the objects in namespace matching the label and field selectors
+func startInformer(client cache.Getter, resource, namespace string, objType runtime.Object, labelSelector, fieldSelector string) cache.SharedIndexInformer {
+	listWatch := cache.NewFilteredListWatchFromClient(client, resource, namespace, func(options *metav1.ListOptions) {
+		options.LabelSelector = labelSelector
+		options.FieldSelector = fieldSelector
+	})
+	informer := cache.NewSharedIndexInformer(listWatch, objType, 0, cache.Indexers{})
+	go informer.Run(wait.NeverStop)
+	return informer
+}
+
+// waitForSync blocks until informer has listed its objects
+func waitForSync(informer cache.SharedIndexInformer) {
+	cache.WaitForCacheSync(wait.NeverStop, informer.HasSynced)
+}
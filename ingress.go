@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	ingressGenerator = "ingressGenerator"
+	// ingress class of mock ingresses, no ingress controller watches it
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+	missingIngressClass    = "kubernetes-events-generator-none"
+)
+
+func init() {
+	registerMock("ingress",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.ExtensionsV1beta1().Ingresses(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.ExtensionsV1beta1().Ingresses(namespace).Delete(name, deleteOptions())
+		})
+}
+
+// ingressEvents returns the events of ingress controllers on ingress name: its lifecycle as seen by
+// nginx-ingress and validation failures of other controllers
+func ingressEvents(name string) []v1.Event {
+	ingress := fmt.Sprintf("%s/%s", defaultNamespace, name)
+	return []v1.Event{
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "CREATE",
+			Message: fmt.Sprintf("Ingress %s", ingress),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "Sync",
+			Message: "Scheduled for sync",
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "Rejected",
+			Message: fmt.Sprintf("%s was rejected: with error: spec.rules[0].host: Invalid value: %q: a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.'", ingress, "*."+name),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "AddedOrUpdatedWithError",
+			Message: fmt.Sprintf("Configuration for %s was added or updated, but not applied: Error reloading NGINX for %s: nginx reload failed: invalid number of arguments in \"proxy_set_header\" directive", ingress, ingress),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "Sync",
+			Message: fmt.Sprintf("Error during sync: error running backend syncing routine: received errors when updating backend service: googleapi: Error 400: Invalid value for field 'resource.port': '0'. Must be greater than or equal to 1, invalid; service %s not found", name),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "UPDATE",
+			Message: fmt.Sprintf("Ingress %s", ingress),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "Sync",
+			Message: "Scheduled for sync",
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "DELETE",
+			Message: fmt.Sprintf("Ingress %s", ingress),
+		},
+	}
+}
+
+// IngressGenerator create ingress controller events on mock ingresses
+type IngressGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many ingresses would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+}
+
+// Name returns the name of IngressGenerator
+func (ig *IngressGenerator) Name() string {
+	return ingressGenerator
+}
+
+// Generate create events on mock ingresses
+func (ig *IngressGenerator) Generate() {
+	ig.initialize()
+	defer ig.finalize()
+}
+
+// Mock and create several ingresses
+func (ig *IngressGenerator) initialize() {
+	for i := 0; i < ig.seed; i++ {
+		mockIngressName := randString(15)
+		var ingress runtime.Object
+		if ig.mock.Phantom {
+			ingress = phantomReference("Ingress", "networking.k8s.io/v1beta1", defaultNamespace, mockIngressName)
+		} else {
+			created, err := ig.createIngress(mockIngressName)
+			if err != nil {
+				fmt.Printf("Failed to create ingress because of %v\n", err)
+				continue
+			}
+			ingress = created
+		}
+
+		for _, e := range ingressEvents(mockIngressName) {
+			ig.recorder.Event(ingress, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create %d ingresses successfully.\n", ig.seed)
+}
+
+// createIngress create a mock ingress named name, through the extensions group on clusters
+// not serving networking.k8s.io/v1beta1 yet
+func (ig *IngressGenerator) createIngress(name string) (runtime.Object, error) {
+	ingress := ig.mock.ingress(name)
+	created, err := ig.clientSet.NetworkingV1beta1().Ingresses(defaultNamespace).Create(ingress)
+	if !errors.IsNotFound(err) {
+		return created, err
+	}
+
+	// both groups share the ingress schema
+	data, err := json.Marshal(ingress)
+	if err != nil {
+		return nil, err
+	}
+	legacy := &extensionsv1beta1.Ingress{}
+	if err := json.Unmarshal(data, legacy); err != nil {
+		return nil, err
+	}
+	return ig.clientSet.ExtensionsV1beta1().Ingresses(defaultNamespace).Create(legacy)
+}
+
+// finalize all ingresses mocked, they are served by the extensions group whichever group created them
+func (ig *IngressGenerator) finalize() {
+	if ig.mock.Phantom {
+		return
+	}
+	err := ig.clientSet.ExtensionsV1beta1().Ingresses(defaultNamespace).DeleteCollection(deleteOptions(), metav1.ListOptions{
+		LabelSelector: runSelector(ig.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to delete mock ingresses,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock ingresses successfully.\n")
+	}
+}
+
+// NewIngressGenerator return new ingress generator instance
+func NewIngressGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions) *IngressGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &IngressGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+	}
+}
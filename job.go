@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+)
+
+const (
+	jobGenerator = "jobGenerator"
+	// retries of mock jobs before they hit their backoff limit
+	jobBackoffLimit = 3
+)
+
+func init() {
+	registerMock("job",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.BatchV1().Jobs(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.BatchV1().Jobs(namespace).Delete(name, deleteOptions())
+		})
+}
+
+// jobEvents returns the job controller's events of job name. Jobs take turns by index i:
+// failing until the backoff limit is hit, running past their deadline, or completing.
+func jobEvents(name string, i int) []v1.Event {
+	created := func() v1.Event {
+		return v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  controller.SuccessfulCreatePodReason,
+			Message: fmt.Sprintf("Created pod: %s-%s", name, randString(5)),
+		}
+	}
+
+	events := []v1.Event{}
+	switch i % 3 {
+	case 0:
+		// the first pod and a retry for every failure
+		for attempt := 0; attempt <= jobBackoffLimit; attempt++ {
+			events = append(events, created())
+		}
+		events = append(events, v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  "BackoffLimitExceeded",
+			Message: "Job has reached the specified backoff limit",
+		})
+	case 1:
+		pod := fmt.Sprintf("%s-%s", name, randString(5))
+		events = append(events,
+			v1.Event{
+				Type:    v1.EventTypeNormal,
+				Reason:  controller.SuccessfulCreatePodReason,
+				Message: fmt.Sprintf("Created pod: %s", pod),
+			},
+			v1.Event{
+				Type:    v1.EventTypeNormal,
+				Reason:  controller.SuccessfulDeletePodReason,
+				Message: fmt.Sprintf("Deleted pod: %s", pod),
+			},
+			v1.Event{
+				Type:    v1.EventTypeWarning,
+				Reason:  "DeadlineExceeded",
+				Message: "Job was active longer than specified deadline",
+			},
+		)
+	default:
+		events = append(events, created(), created(), v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  "Completed",
+			Message: "Job completed",
+		})
+	}
+	return events
+}
+
+// JobGenerator create events on mock jobs
+type JobGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many jobs would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+}
+
+// Name returns the name of JobGenerator
+func (jg *JobGenerator) Name() string {
+	return jobGenerator
+}
+
+// Generate create events on mock jobs
+func (jg *JobGenerator) Generate() {
+	jg.initialize()
+	defer jg.finalize()
+}
+
+// Mock and create several jobs
+func (jg *JobGenerator) initialize() {
+	for i := 0; i < jg.seed; i++ {
+		mockJobName := randString(15)
+		var job runtime.Object
+		if jg.mock.Phantom {
+			job = phantomReference("Job", "batch/v1", defaultNamespace, mockJobName)
+		} else {
+			created, err := jg.clientSet.BatchV1().Jobs(defaultNamespace).Create(jg.mock.job(mockJobName))
+			if err != nil {
+				fmt.Printf("Failed to create job because of %v\n", err)
+				continue
+			}
+			job = created
+		}
+
+		for _, e := range jobEvents(mockJobName, i) {
+			jg.recorder.Event(job, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create %d jobs successfully.\n", jg.seed)
+}
+
+// finalize all jobs mocked
+func (jg *JobGenerator) finalize() {
+	if jg.mock.Phantom {
+		return
+	}
+	err := jg.clientSet.BatchV1().Jobs(defaultNamespace).DeleteCollection(deleteOptions(), metav1.ListOptions{
+		LabelSelector: runSelector(jg.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to delete mock jobs,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock jobs successfully.\n")
+	}
+}
+
+// NewJobGenerator return new job generator instance
+func NewJobGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions) *JobGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &JobGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+	}
+}
@@ -3,13 +3,14 @@ package main
 import (
 	"flag"
 	"fmt"
+	"k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
-	"k8s.io/api/core/v1"
-	"k8s.io/client-go/kubernetes/scheme"
-	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"time"
 )
 
 const (
@@ -19,10 +20,15 @@ const (
 // manager of different kinds of generator
 type GeneratorManager struct {
 	generators map[string]Generator
+	names      []string // registration order, keeps seeded runs reproducible
+	afterPass  []func() // run after every generator had its turn
 }
 
 func (gm *GeneratorManager) register(generator Generator) {
 	if name := generator.Name(); name != "" {
+		if _, ok := gm.generators[name]; !ok {
+			gm.names = append(gm.names, name)
+		}
 		gm.generators[name] = generator
 	}
 }
@@ -30,16 +36,21 @@ func (gm *GeneratorManager) register(generator Generator) {
 func (gm *GeneratorManager) run() {
 	for {
 		// run forever
-		for name, generator := range gm.generators {
+		for _, name := range gm.names {
 			fmt.Printf("%s events generator started\n", name)
-			generator.Generate()
+			gm.generators[name].Generate()
+		}
+		for _, f := range gm.afterPass {
+			f()
 		}
 	}
 }
 
 var generatorManager *GeneratorManager
 
-func init() {
+func main() {
+	// parse flag before building generators, they are configured by flags
+	flag.Parse()
 
 	config, err := clientcmd.BuildConfigFromFlags("", "")
 	if err != nil {
@@ -51,6 +62,11 @@ func init() {
 		panic(err)
 	}
 
+	if flag.Arg(0) == cleanupCommand {
+		runCleanup(clientSet, flag.Args()[1:])
+		return
+	}
+
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(klog.Infof)
 	eventBroadcaster.StartRecordingToSink(
@@ -60,16 +76,86 @@ func init() {
 		scheme.Scheme,
 		v1.EventSource{Component: kubernetesEventsGenerator})
 
+	fmt.Printf("Random seed is %d\n", seedRandom(*randomSeed))
+
+	profile, err := newLoadProfile(*loadProfile)
+	if err != nil {
+		panic(err)
+	}
+	pacer = NewPacer(*loadProfile, profile)
+	if *metricsAddress != "" {
+		go serveMetrics(*metricsAddress)
+	}
+
 	generatorManager = &GeneratorManager{
 		generators: make(map[string]Generator),
 	}
-	generatorManager.register(NewDeploymentGenerator(clientSet, recorder, 0))
-	generatorManager.register(NewNodeGenerator(clientSet, recorder))
-	generatorManager.register(NewPodGenerator(clientSet, recorder, 0))
-}
 
-func main() {
-	// parse flag
-	flag.Parse()
+	mock, err := NewMockOptions()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Run id is %s\n", mock.RunID)
+	rr := NewRunRecorder(recorder, mock.RunID)
+	recorder = rr
+
+	// phantom runs create nothing, neither a lease nor do they sweep other runs
+	if !mock.Phantom {
+		// take the lease first so the sweeper never sees this run as dead
+		if err := renewLease(clientSet, mock); err != nil {
+			fmt.Printf("Failed to create lease of run %s,because of %v\n", mock.RunID, err)
+		}
+		go heartbeat(clientSet, mock)
+		if *sweepInterval > 0 {
+			sweepOrphans(clientSet)
+			go runSweeper(clientSet, *sweepInterval)
+		}
+	}
+
+	if *cleanupEvents {
+		generatorManager.afterPass = append(generatorManager.afterPass, func() {
+			time.Sleep(eventsDrainDelay)
+			deleteEvents(clientSet, mock.RunID, rr.takeInvolved())
+		})
+	}
+
+	// replay mode re-emits captured events instead of generating new ones
+	if *replayFile != "" {
+		generatorManager.register(NewReplayGenerator(clientSet, recorder, mock, *replayFile, *replaySpeed, *replayNamespace))
+	} else {
+		// owner chains are made of mock objects, existing workloads cannot be chained
+		if *enableOwnerChain && *targetExisting {
+			panic(fmt.Errorf("--owner-chain cannot be combined with --target-existing"))
+		}
+		targets, err := NewTargets(clientSet)
+		if err != nil {
+			panic(err)
+		}
+		generatorManager.register(NewDeploymentGenerator(clientSet, recorder, 0, mock, targets))
+		nodeFilter, err := NewNodeFilter()
+		if err != nil {
+			panic(err)
+		}
+		nodes := NewNodeGenerator(clientSet, recorder, mock, nodeFilter)
+		generatorManager.register(nodes)
+		// later generators of the pass name and emit on the synthetic nodes, keep them until the pass is over
+		generatorManager.afterPass = append(generatorManager.afterPass, nodes.deleteSyntheticNodes)
+		generatorManager.register(NewPodGenerator(clientSet, recorder, 0, mock, targets))
+		generatorManager.register(NewSchedulerGenerator(clientSet, recorder, 0, mock, nodes))
+		generatorManager.register(NewReplicaSetGenerator(clientSet, recorder, 0, mock))
+		generatorManager.register(NewStatefulSetGenerator(clientSet, recorder, 0, mock))
+		generatorManager.register(NewDaemonSetGenerator(clientSet, recorder, 0, mock, nodes))
+		generatorManager.register(NewServiceGenerator(clientSet, recorder, 0, mock, nodes))
+		generatorManager.register(NewIngressGenerator(clientSet, recorder, 0, mock))
+		generatorManager.register(NewVolumeGenerator(clientSet, recorder, 0, mock))
+		generatorManager.register(NewHPAGenerator(clientSet, recorder, 0, mock))
+		generatorManager.register(NewClusterAutoscalerGenerator(clientSet, recorder, 0, mock, nodes))
+		generatorManager.register(NewJobGenerator(clientSet, recorder, 0, mock))
+		generatorManager.register(NewCronJobGenerator(clientSet, recorder, 0, mock))
+		if *enableOwnerChain {
+			generatorManager.register(NewOwnerChainGenerator(clientSet, recorder, 0, mock))
+		}
+	}
+
 	generatorManager.run()
 }
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+)
+
+var (
+	metricsAddress = flag.String("metrics-address", "", "address to expose prometheus metrics on, e.g. :8080; empty disables metrics")
+)
+
+// metricsHandler writes the load profile metrics in prometheus text format
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	rate, events := pacer.Stats()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP kubernetes_events_generator_load_profile_rate Target events per second of the load profile.\n")
+	fmt.Fprintf(w, "# TYPE kubernetes_events_generator_load_profile_rate gauge\n")
+	fmt.Fprintf(w, "kubernetes_events_generator_load_profile_rate{profile=%q} %g\n", pacer.name, rate)
+	fmt.Fprintf(w, "# HELP kubernetes_events_generator_paced_events_total Events emitted under the load profile.\n")
+	fmt.Fprintf(w, "# TYPE kubernetes_events_generator_paced_events_total counter\n")
+	fmt.Fprintf(w, "kubernetes_events_generator_paced_events_total{profile=%q} %d\n", pacer.name, events)
+}
+
+// serveMetrics exposes metrics on address until the process exits
+func serveMetrics(address string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", metricsHandler)
+	if err := http.ListenAndServe(address, mux); err != nil {
+		fmt.Printf("Failed to serve metrics on %s,because of %v\n", address, err)
+	}
+}
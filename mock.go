@@ -0,0 +1,623 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	"k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	generatorLabel = "gen-by"
+	runIDLabel     = "gen-run-id"
+
+	inertImage            = "k8s.gcr.io/pause:3.1"
+	inertNodeSelectorKey  = "kubernetes-events-generator/inert"
+	inertNodeSelectorNone = "no-node-carries-this-label"
+
+	syntheticNodeTaintKey = "kubernetes-events-generator/synthetic"
+)
+
+var (
+	inert                  = flag.Bool("inert", false, "create mock workloads that exist but never run, zero replica paused deployments and unschedulable pause pods")
+	podTemplateFile        = flag.String("pod-template", "", "yaml or json PodTemplateSpec (or Pod) file mock pods are built from")
+	deploymentTemplateFile = flag.String("deployment-template", "", "yaml or json Deployment file mock deployments are built from")
+	syntheticNodes         = flag.Int("synthetic-nodes", 0, "create this many fake nodes no kubelet manages and emit node events on them instead of the real nodes")
+)
+
+// MockOptions controls how generators build their mock objects
+type MockOptions struct {
+	Phantom        bool          // emit events on fictitious objects and create nothing
+	Inert          bool          // create objects that never run
+	RunID          string        // identifies the objects of this run
+	TTL            time.Duration // how long mock objects may outlive a crashed run
+	SyntheticNodes int           // fake nodes node events go to instead of the real ones
+
+	PatchNodeConditions bool // keep the conditions of synthetic nodes in line with their events
+	PatchPodStatus      bool // keep the container statuses of mock pods in line with their events
+	PodTemplate         *v1.PodTemplateSpec
+	DeploymentTemplate  *appsv1.Deployment
+}
+
+// NewMockOptions return mock options configured by flags, templates are loaded from their files
+func NewMockOptions() (*MockOptions, error) {
+	mo := &MockOptions{
+		Phantom: *phantom,
+		Inert:   *inert,
+		RunID:   newRunID(),
+		TTL:     *objectTTL,
+
+		SyntheticNodes:      *syntheticNodes,
+		PatchNodeConditions: *patchNodeConditions,
+		PatchPodStatus:      *patchPodStatus,
+	}
+	if *podTemplateFile != "" {
+		mo.PodTemplate = &v1.PodTemplateSpec{}
+		if err := decodeFile(*podTemplateFile, mo.PodTemplate); err != nil {
+			return nil, fmt.Errorf("failed to load pod template %s: %v", *podTemplateFile, err)
+		}
+	}
+	if *deploymentTemplateFile != "" {
+		mo.DeploymentTemplate = &appsv1.Deployment{}
+		if err := decodeFile(*deploymentTemplateFile, mo.DeploymentTemplate); err != nil {
+			return nil, fmt.Errorf("failed to load deployment template %s: %v", *deploymentTemplateFile, err)
+		}
+	}
+	return mo, nil
+}
+
+// newRunID returns a run id from the clock, it doesn't depend on the random seed
+// so reproducible runs can still run side by side
+func newRunID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// decodeFile decodes the yaml or json object in file into obj
+func decodeFile(file string, obj interface{}) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return yaml.NewYAMLOrJSONDecoder(f, 4096).Decode(obj)
+}
+
+// mockLabels returns the labels every mock object carries
+func (mo *MockOptions) mockLabels() map[string]string {
+	return map[string]string{
+		generatorLabel: kubernetesEventsGenerator,
+		runIDLabel:     mo.RunID,
+	}
+}
+
+// mockAnnotations returns the annotations every mock object carries
+func (mo *MockOptions) mockAnnotations() map[string]string {
+	if mo.TTL <= 0 {
+		return nil
+	}
+	return map[string]string{
+		ttlAnnotation: mo.TTL.String(),
+	}
+}
+
+// withMockAnnotations adds the mock annotations to annotations
+func (mo *MockOptions) withMockAnnotations(annotations map[string]string) map[string]string {
+	for k, v := range mo.mockAnnotations() {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// withMockLabels adds the mock labels to labels
+func (mo *MockOptions) withMockLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range mo.mockLabels() {
+		labels[k] = v
+	}
+	return labels
+}
+
+// inertSpec makes spec unschedulable
+func (mo *MockOptions) inertSpec(spec *v1.PodSpec) {
+	// no node carries the label so the pod stays Pending. Binding the pod to a
+	// non-existent node instead would get it deleted by the pod garbage collector.
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	spec.NodeSelector[inertNodeSelectorKey] = inertNodeSelectorNone
+}
+
+// podSpec returns the built-in spec of mock pods and deployment templates
+func (mo *MockOptions) podSpec() v1.PodSpec {
+	if mo.Inert {
+		spec := v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  "web",
+					Image: inertImage,
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("1m"),
+							v1.ResourceMemory: resource.MustParse("4Mi"),
+						},
+						Limits: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("1m"),
+							v1.ResourceMemory: resource.MustParse("4Mi"),
+						},
+					},
+				},
+			},
+		}
+		mo.inertSpec(&spec)
+		return spec
+	}
+	return v1.PodSpec{
+		Containers: []v1.Container{
+			{
+				Name:  "web",
+				Image: "nginx:1.12",
+				Ports: []v1.ContainerPort{
+					{
+						Name:          "http",
+						Protocol:      v1.ProtocolTCP,
+						ContainerPort: 80,
+					},
+				},
+			},
+		},
+	}
+}
+
+// pod returns a mock pod named name, built from the pod template if there is one
+func (mo *MockOptions) pod(name string) *v1.Pod {
+	if mo.PodTemplate == nil {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      mo.mockLabels(),
+				Annotations: mo.mockAnnotations(),
+			},
+			Spec: mo.podSpec(),
+		}
+	}
+
+	template := mo.PodTemplate.DeepCopy()
+	// only labels and annotations are taken over, uid, owner references, finalizers and alike of an exported pod are not
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.withMockLabels(template.Labels),
+			Annotations: mo.withMockAnnotations(template.Annotations),
+		},
+		Spec: template.Spec,
+	}
+	// an exported pod is bound to the node it ran on, leave the choice to the scheduler
+	pod.Spec.NodeName = ""
+	if mo.Inert {
+		mo.inertSpec(&pod.Spec)
+	}
+	return pod
+}
+
+// deployment returns a mock deployment named name, built from the deployment template if there is one.
+// Inert deployments have no replicas and are paused.
+func (mo *MockOptions) deployment(name string) *appsv1.Deployment {
+	if mo.DeploymentTemplate == nil {
+		replicas := int32(2)
+		if mo.Inert {
+			replicas = 0
+		}
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      mo.mockLabels(),
+				Annotations: mo.mockAnnotations(),
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(replicas),
+				Paused:   mo.Inert,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app": name,
+					},
+				},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app": name,
+						},
+					},
+					Spec: mo.podSpec(),
+				},
+			},
+		}
+	}
+
+	deployment := mo.DeploymentTemplate.DeepCopy()
+	deployment.ObjectMeta = metav1.ObjectMeta{
+		Name:        name,
+		Labels:      mo.withMockLabels(deployment.Labels),
+		Annotations: mo.withMockAnnotations(deployment.Annotations),
+	}
+	deployment.Status = appsv1.DeploymentStatus{}
+	deployment.Spec.Template.Labels = mo.withMockLabels(deployment.Spec.Template.Labels)
+	if deployment.Spec.Selector == nil {
+		deployment.Spec.Template.Labels["app"] = name
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app": name,
+			},
+		}
+	}
+	if mo.Inert {
+		deployment.Spec.Replicas = int32Ptr(0)
+		deployment.Spec.Paused = true
+		mo.inertSpec(&deployment.Spec.Template.Spec)
+	}
+	return deployment
+}
+
+// replicaSet returns a mock replica set named name, it shares the spec of mock deployments.
+// Inert replica sets have no replicas.
+func (mo *MockOptions) replicaSet(name string) *appsv1.ReplicaSet {
+	deployment := mo.deployment(name)
+	return &appsv1.ReplicaSet{
+		ObjectMeta: deployment.ObjectMeta,
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: deployment.Spec.Replicas,
+			Selector: deployment.Spec.Selector,
+			Template: deployment.Spec.Template,
+		},
+	}
+}
+
+// replicationController returns a mock replication controller named name, it shares the spec of mock deployments.
+// Inert replication controllers have no replicas.
+func (mo *MockOptions) replicationController(name string) *v1.ReplicationController {
+	deployment := mo.deployment(name)
+	template := deployment.Spec.Template
+	// replication controllers only select on labels, keep them in line with the template
+	selector := deployment.Spec.Selector.MatchLabels
+	if len(selector) == 0 {
+		selector = map[string]string{"app": name}
+		template.Labels = mo.withMockLabels(template.Labels)
+		template.Labels["app"] = name
+	}
+	return &v1.ReplicationController{
+		ObjectMeta: deployment.ObjectMeta,
+		Spec: v1.ReplicationControllerSpec{
+			Replicas: deployment.Spec.Replicas,
+			Selector: selector,
+			Template: &template,
+		},
+	}
+}
+
+// statefulSet returns a mock stateful set named name with a claim template.
+// It never has replicas, so no pods are started and no volumes are provisioned.
+func (mo *MockOptions) statefulSet(name string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.mockLabels(),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    int32Ptr(0),
+			ServiceName: name,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": name,
+				},
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": name,
+					},
+				},
+				Spec: mo.podSpec(),
+			},
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: statefulSetClaimTemplate,
+					},
+					Spec: v1.PersistentVolumeClaimSpec{
+						AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{
+								v1.ResourceStorage: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// daemonSet returns a mock daemon set named name. Its pods select a node label no node
+// carries, so the daemon set never runs a pod whether inert or not.
+func (mo *MockOptions) daemonSet(name string) *appsv1.DaemonSet {
+	spec := mo.podSpec()
+	mo.inertSpec(&spec)
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.mockLabels(),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": name,
+				},
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": name,
+					},
+				},
+				Spec: spec,
+			},
+		},
+	}
+}
+
+// jobSpec returns the spec of mock jobs. No parallelism, so the job never starts a pod.
+func (mo *MockOptions) jobSpec() batchv1.JobSpec {
+	spec := mo.podSpec()
+	spec.RestartPolicy = v1.RestartPolicyNever
+	return batchv1.JobSpec{
+		Parallelism:  int32Ptr(0),
+		BackoffLimit: int32Ptr(jobBackoffLimit),
+		Template: v1.PodTemplateSpec{
+			Spec: spec,
+		},
+	}
+}
+
+// job returns a mock job named name
+func (mo *MockOptions) job(name string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.mockLabels(),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: mo.jobSpec(),
+	}
+}
+
+// cronJob returns a suspended mock cron job named name, it never creates a job
+func (mo *MockOptions) cronJob(name string) *batchv1beta1.CronJob {
+	suspend := true
+	return &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.mockLabels(),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule: cronJobSchedule,
+			Suspend:  &suspend,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: mo.mockLabels(),
+				},
+				Spec: mo.jobSpec(),
+			},
+		},
+	}
+}
+
+// service returns a mock ClusterIP service named name, no cloud load balancer is provisioned for it
+func (mo *MockOptions) service(name string) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.mockLabels(),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: v1.ServiceSpec{
+			Type: v1.ServiceTypeClusterIP,
+			Selector: map[string]string{
+				"app": name,
+			},
+			Ports: []v1.ServicePort{
+				{
+					Name:       "http",
+					Protocol:   v1.ProtocolTCP,
+					Port:       80,
+					TargetPort: intstr.FromString("http"),
+				},
+			},
+		},
+	}
+}
+
+// persistentVolumeClaim returns a mock claim named name of a storage class that does not exist,
+// it stays Pending forever
+func (mo *MockOptions) persistentVolumeClaim(name string) *v1.PersistentVolumeClaim {
+	storageClass := missingStorageClass
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.mockLabels(),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: resource.MustParse("10Gi"),
+				},
+			},
+		},
+	}
+}
+
+// persistentVolume returns a mock volume named name. Its storage class differs from the one of mock
+// claims so it is never bound, and no driver serves its source.
+func (mo *MockOptions) persistentVolume(name string) *v1.PersistentVolume {
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.mockLabels(),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: v1.PersistentVolumeSpec{
+			StorageClassName:              missingStorageClass + "-static",
+			AccessModes:                   []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+			Capacity: v1.ResourceList{
+				v1.ResourceStorage: resource.MustParse("10Gi"),
+			},
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{
+					Driver:       kubernetesEventsGenerator,
+					VolumeHandle: name,
+				},
+			},
+		},
+	}
+}
+
+// horizontalPodAutoscaler returns a mock autoscaler named name of the deployment named target
+func (mo *MockOptions) horizontalPodAutoscaler(name, target string) *autoscalingv1.HorizontalPodAutoscaler {
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      mo.mockLabels(),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       target,
+			},
+			MinReplicas:                    int32Ptr(hpaMinReplicas),
+			MaxReplicas:                    hpaMaxReplicas,
+			TargetCPUUtilizationPercentage: int32Ptr(hpaTargetUtilization),
+		},
+	}
+}
+
+// ingress returns a mock ingress named name of an ingress class no controller watches
+func (mo *MockOptions) ingress(name string) *networkingv1beta1.Ingress {
+	return &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: mo.mockLabels(),
+			Annotations: mo.withMockAnnotations(map[string]string{
+				ingressClassAnnotation: missingIngressClass,
+			}),
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: fmt.Sprintf("%s.example.com", name),
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: name,
+										ServicePort: intstr.FromInt(80),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// node returns a synthetic node named name. It looks healthy but no kubelet manages it,
+// the node lifecycle controller will mark it NotReady after its grace period.
+// The taint keeps real pods away from it.
+func (mo *MockOptions) node(name string) *v1.Node {
+	capacity := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("16Gi"),
+		v1.ResourcePods:   resource.MustParse("110"),
+	}
+	now := metav1.Now()
+	condition := func(conditionType v1.NodeConditionType, status v1.ConditionStatus, reason, message string) v1.NodeCondition {
+		return v1.NodeCondition{
+			Type:               conditionType,
+			Status:             status,
+			LastHeartbeatTime:  now,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+		}
+	}
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: mo.withMockLabels(map[string]string{
+				"kubernetes.io/hostname": name,
+				"kubernetes.io/os":       "linux",
+				"kubernetes.io/arch":     "amd64",
+				// keep the node out of the host sets of real LoadBalancer services
+				"alpha.service-controller.kubernetes.io/exclude-balancer": "true",
+				"node.kubernetes.io/exclude-from-external-load-balancers": "true",
+			}),
+			Annotations: mo.mockAnnotations(),
+		},
+		Spec: v1.NodeSpec{
+			Taints: []v1.Taint{
+				{
+					Key:    syntheticNodeTaintKey,
+					Value:  "true",
+					Effect: v1.TaintEffectNoSchedule,
+				},
+			},
+		},
+		Status: v1.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: capacity,
+			Phase:       v1.NodeRunning,
+			Conditions: []v1.NodeCondition{
+				condition(v1.NodeMemoryPressure, v1.ConditionFalse, "KubeletHasSufficientMemory", "kubelet has sufficient memory available"),
+				condition(v1.NodeDiskPressure, v1.ConditionFalse, "KubeletHasNoDiskPressure", "kubelet has no disk pressure"),
+				condition(v1.NodePIDPressure, v1.ConditionFalse, "KubeletHasSufficientPID", "kubelet has sufficient PID available"),
+				condition(v1.NodeReady, v1.ConditionTrue, "KubeletReady", "kubelet is posting ready status"),
+			},
+			Addresses: []v1.NodeAddress{
+				{Type: v1.NodeHostName, Address: name},
+			},
+			NodeInfo: v1.NodeSystemInfo{
+				KubeletVersion:          "v1.14.1",
+				KubeProxyVersion:        "v1.14.1",
+				OperatingSystem:         "linux",
+				Architecture:            "amd64",
+				ContainerRuntimeVersion: "docker://18.9.2",
+			},
+		},
+	}
+}
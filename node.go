@@ -1,21 +1,32 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/kubelet/events"
+	"regexp"
+	"sort"
 	"strings"
-	"time"
 )
 
 const (
 	nodeGenerator = "nodeEventsGenerator"
 )
 
+var (
+	nodeSelector      = flag.String("node-selector", "", "label selector limiting the real nodes node events are emitted on")
+	nodeFieldSelector = flag.String("node-field-selector", "", "field selector limiting the real nodes node events are emitted on")
+	nodeNameRegex     = flag.String("node-name-regex", "", "regular expression node names must match to get node events")
+	nodeSample        = flag.Int("node-sample", 0, "emit node events on a random sample of this many matching nodes every pass; 0 uses all of them")
+)
+
 var (
 	nodeEvents = []v1.Event{
 		v1.Event{
@@ -256,16 +267,59 @@ var (
 	}
 )
 
+func init() {
+	registerMock("node",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.CoreV1().Nodes().List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.CoreV1().Nodes().Delete(name, &metav1.DeleteOptions{})
+		})
+}
+
+// NodeFilter limits the real nodes node events are emitted on
+type NodeFilter struct {
+	Selector      string
+	FieldSelector string
+	NameRegex     *regexp.Regexp
+	Sample        int
+}
+
+// NewNodeFilter return node filter configured by flags
+func NewNodeFilter() (*NodeFilter, error) {
+	if err := validateSelectors(*nodeSelector, *nodeFieldSelector); err != nil {
+		return nil, err
+	}
+	filter := &NodeFilter{
+		Selector:      *nodeSelector,
+		FieldSelector: *nodeFieldSelector,
+		Sample:        *nodeSample,
+	}
+	if *nodeNameRegex != "" {
+		regex, err := regexp.Compile(*nodeNameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node name regex %q: %v", *nodeNameRegex, err)
+		}
+		filter.NameRegex = regex
+	}
+	return filter, nil
+}
+
 // node events generator
 type NodeGenerator struct {
 	clientSet kubernetes.Interface
 	recorder  record.EventRecorder
+	mock      *MockOptions
+	filter    *NodeFilter
+	informer  cache.SharedIndexInformer // real nodes, nil when they are not targeted
+	passNodes []v1.Node                 // phantom or synthetic nodes of the current pass
+	sampled   map[string]bool           // names of the real nodes sampled for the current pass
 }
 
 // Generate node events
 func (ng *NodeGenerator) Generate() {
 	ng.initialize()
-	// no need to finalize the nodes.
+	defer ng.finalize()
 }
 
 // Name return node events generator's name
@@ -273,30 +327,139 @@ func (ng *NodeGenerator) Name() string {
 	return nodeGenerator
 }
 
-// initialize fetch all nodes and emit events on those nodes
+// initialize pick the nodes of this pass and emit events on those nodes
 func (ng *NodeGenerator) initialize() {
-	fmt.Printf("node event generator started.\n", )
-	nodeList, err := ng.clientSet.CoreV1().Nodes().List(metav1.ListOptions{})
-	if err != nil {
-		fmt.Printf("Failed to fetch all nodes in cluster,because of %v", err)
-		return
-	}
+	fmt.Printf("node event generator started.\n")
+	ng.preparePass()
+
+	emitted := map[string]bool{}
 	for _, event := range nodeEvents {
-		for _, node := range nodeList.Items {
+		// nodes may come and go during a long pass, fetch them again for every event
+		nodes := ng.nodes()
+		for i := range nodes {
+			node := &nodes[i]
+			message := event.Message
 			if strings.Contains(event.Message, "%s") {
-				ng.recorder.Event(&node, event.Type, event.Reason, fmt.Sprintf(event.Message, node.Name))
-			} else {
-				ng.recorder.Event(&node, event.Type, event.Reason, event.Message)
+				message = fmt.Sprintf(event.Message, node.Name)
+			}
+			ng.recorder.Event(node, event.Type, event.Reason, message)
+			if ng.mock.PatchNodeConditions && ng.mock.SyntheticNodes > 0 && !ng.mock.Phantom {
+				ng.patchNodeCondition(node, event.Reason, message)
 			}
-			time.Sleep(3 * time.Second)
+			emitted[node.Name] = true
+			pace()
 		}
 	}
 
-	fmt.Printf("Create %d nodes' events successfully.\n", len(nodeList.Items))
+	fmt.Printf("Create %d nodes' events successfully.\n", len(emitted))
+}
+
+// preparePass picks the nodes of this pass, fictitious ones in phantom mode,
+// newly created synthetic ones if asked for, or a sample of the real ones
+func (ng *NodeGenerator) preparePass() {
+	switch {
+	case ng.mock.Phantom:
+		ng.passNodes = phantomNodeList(*phantomNodes).Items
+	case ng.mock.SyntheticNodes > 0:
+		ng.passNodes = ng.createNodes().Items
+	default:
+		waitForSync(ng.informer)
+		ng.sampled = nil
+		if ng.filter.Sample > 0 {
+			nodes := ng.realNodes()
+			ng.sampled = map[string]bool{}
+			for i := 0; i < len(nodes) && i < ng.filter.Sample; i++ {
+				j := i + randIntn(len(nodes)-i)
+				nodes[i], nodes[j] = nodes[j], nodes[i]
+				ng.sampled[nodes[i].Name] = true
+			}
+		}
+	}
 }
-func NewNodeGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder) *NodeGenerator {
-	return &NodeGenerator{
+
+// nodes returns the nodes to emit the next event on
+func (ng *NodeGenerator) nodes() []v1.Node {
+	if ng.informer == nil {
+		return ng.passNodes
+	}
+	nodes := ng.realNodes()
+	if ng.sampled == nil {
+		return nodes
+	}
+	sampled := []v1.Node{}
+	for _, node := range nodes {
+		if ng.sampled[node.Name] {
+			sampled = append(sampled, node)
+		}
+	}
+	return sampled
+}
+
+// realNodes returns the cached real nodes matching the filter, sorted by name
+func (ng *NodeGenerator) realNodes() []v1.Node {
+	nodes := []v1.Node{}
+	for _, obj := range ng.informer.GetStore().List() {
+		node := obj.(*v1.Node)
+		if ng.filter.NameRegex != nil && !ng.filter.NameRegex.MatchString(node.Name) {
+			continue
+		}
+		nodes = append(nodes, *node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
+
+// createNodes create the synthetic nodes of the run
+func (ng *NodeGenerator) createNodes() *v1.NodeList {
+	nodeList := &v1.NodeList{}
+	for i := 0; i < ng.mock.SyntheticNodes; i++ {
+		node, err := ng.clientSet.CoreV1().Nodes().Create(ng.mock.node(syntheticNodeName(ng.mock.RunID, i)))
+		if err != nil {
+			fmt.Printf("Failed to create synthetic node,because of %v\n", err)
+			continue
+		}
+		nodeList.Items = append(nodeList.Items, *node)
+	}
+	return nodeList
+}
+
+// finalize nothing, the synthetic nodes are used by other generators for the rest of the pass
+func (ng *NodeGenerator) finalize() {
+}
+
+// deleteSyntheticNodes remove the synthetic nodes after the pass, real nodes are left alone
+func (ng *NodeGenerator) deleteSyntheticNodes() {
+	if ng.mock.Phantom || ng.mock.SyntheticNodes <= 0 {
+		return
+	}
+	err := ng.clientSet.CoreV1().Nodes().DeleteCollection(&metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: runSelector(ng.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to delete synthetic nodes,because of %v\n", err)
+	} else {
+		fmt.Print("Delete synthetic nodes successfully.\n")
+	}
+}
+
+// syntheticNodeName returns the name of the i-th synthetic node of run
+func syntheticNodeName(runID string, i int) string {
+	return fmt.Sprintf("evgen-node-%d-%s", i, runID)
+}
+
+// NewNodeGenerator return new node generator instance, it watches the real nodes matching filter
+// unless phantom or synthetic nodes are used
+func NewNodeGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, mock *MockOptions, filter *NodeFilter) *NodeGenerator {
+	ng := &NodeGenerator{
 		clientSet: clientSet,
 		recorder:  recorder,
+		mock:      mock,
+		filter:    filter,
+	}
+	if !mock.Phantom && mock.SyntheticNodes <= 0 {
+		ng.informer = startInformer(clientSet.CoreV1().RESTClient(), "nodes", metav1.NamespaceAll, &v1.Node{}, filter.Selector, filter.FieldSelector)
 	}
+	return ng
 }
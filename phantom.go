@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	phantom      = flag.Bool("phantom", false, "emit events on fictitious objects instead of creating real ones, nothing is scheduled and nothing needs cleanup")
+	phantomNodes = flag.Int("phantom-nodes", 3, "number of fictitious nodes node events are emitted on in phantom mode")
+)
+
+// phantomReference returns a reference to a fictitious object with a synthetic uid
+func phantomReference(kind, apiVersion, namespace, name string) *v1.ObjectReference {
+	return &v1.ObjectReference{
+		Kind:       kind,
+		APIVersion: apiVersion,
+		Namespace:  namespace,
+		Name:       name,
+		UID:        newUID(),
+	}
+}
+
+// phantomNodeList returns count fictitious nodes, they only live in memory
+func phantomNodeList(count int) *v1.NodeList {
+	nodeList := &v1.NodeList{}
+	for i := 0; i < count; i++ {
+		nodeList.Items = append(nodeList.Items, v1.Node{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Node",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("phantom-node-%d", i),
+				UID:  newUID(),
+			},
+		})
+	}
+	return nodeList
+}
@@ -4,11 +4,10 @@ import (
 	"fmt"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/kubernetes/pkg/kubelet/events"
-	"time"
 )
 
 // const vars
@@ -213,34 +212,12 @@ var (
 			Reason:  events.BackOffPullImage,
 			Message: "Back-off pulling image",
 		},
-		v1.Event{
-			Type:    v1.EventTypeWarning,
-			Reason:  "FailedScheduling",
-			Message: "AssumePod failed",
-		},
-		v1.Event{
-			Type:    v1.EventTypeNormal,
-			Reason:  "Scheduled",
-			Message: "Successfully assigned",
-		},
-		v1.Event{
-			Type:    v1.EventTypeNormal,
-			Reason:  "Preempted",
-			Message: "by on node",
-		},
-
 		v1.Event{
 			Type:    v1.EventTypeWarning,
 			Reason:  "TaintManagerEviction",
 			Message: "Cancelling deletion of Pod",
 		},
 
-		v1.Event{
-			Type:    v1.EventTypeWarning,
-			Reason:  "FailedScheduling",
-			Message: "AssumePod failed",
-		},
-
 		v1.Event{
 			Type:    v1.EventTypeWarning,
 			Reason:  "MissingClusterDNS",
@@ -249,11 +226,23 @@ var (
 	}
 )
 
+func init() {
+	registerMock("pod",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.CoreV1().Pods(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.CoreV1().Pods(namespace).Delete(name, &metav1.DeleteOptions{})
+		})
+}
+
 // Pod events generator
 type PodGenerator struct {
 	clientSet kubernetes.Interface
 	seed      int
 	recorder  record.EventRecorder
+	mock      *MockOptions
+	targets   *Targets // existing pods to emit on instead of mocks, if any
 }
 
 // Generate pod events
@@ -269,48 +258,74 @@ func (pg *PodGenerator) Name() string {
 
 // initialize create mock pods
 func (pg *PodGenerator) initialize() {
+	if pg.targets != nil {
+		pg.initializeTargets()
+		return
+	}
 	for i := 0; i < pg.seed; i++ {
-		mockPodName := rand.String(15)
-		pod, err := pg.clientSet.CoreV1().Pods(defaultNamespace).Create(&v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: mockPodName,
-				Labels: map[string]string{
-					"gen-by": kubernetesEventsGenerator,
-				},
-			},
-			Spec: v1.PodSpec{
-				Containers: []v1.Container{
-					{
-						Name:  "web",
-						Image: "nginx:1.12",
-						Ports: []v1.ContainerPort{
-							{
-								Name:          "http",
-								Protocol:      v1.ProtocolTCP,
-								ContainerPort: 80,
-							},
-						},
-					},
-				},
-			},
-		})
+		mockPodName := randString(15)
+		// the pod events are spread over several pods, the recorder would drop the events past
+		// maxEventsPerObject on a single one, leaving patched statuses without their events
+		for part, start := 0, 0; start < len(podEvents); part, start = part+1, start+maxEventsPerObject {
+			end := start + maxEventsPerObject
+			if end > len(podEvents) {
+				end = len(podEvents)
+			}
+			pg.emit(fmt.Sprintf("%s-%d", mockPodName, part), podEvents[start:end])
+		}
+	}
+	fmt.Printf("Create %d pods successfully.\n", pg.seed)
+}
 
+// emit events on a phantom or newly created pod named name
+func (pg *PodGenerator) emit(name string, part []v1.Event) {
+	var pod runtime.Object
+	var created *v1.Pod
+	if pg.mock.Phantom {
+		pod = phantomReference("Pod", "v1", defaultNamespace, name)
+	} else {
+		var err error
+		created, err = pg.createPod(name)
 		if err != nil {
 			fmt.Printf("Failed to create pod,because of %v\n", err)
-			continue
+			return
 		}
+		pod = created
+	}
+	for i := range part {
+		event := &part[i]
+		pg.recorder.Event(pod, event.Type, event.Reason, event.Message)
+		if pg.mock.PatchPodStatus && created != nil {
+			created = pg.patchPodStatus(created, event)
+		}
+		pace()
+	}
+}
+
+// initializeTargets emit pod events on the targeted existing pods, they are never modified
+func (pg *PodGenerator) initializeTargets() {
+	pods := pg.targets.Pods()
+	for _, pod := range pods {
 		for _, event := range podEvents {
 			pg.recorder.Event(pod, event.Type, event.Reason, event.Message)
-			time.Sleep(3 * time.Second)
+			pace()
 		}
 	}
-	fmt.Printf("Create %d pods successfully.\n", pg.seed)
+	fmt.Printf("Create events on %d existing pods successfully.\n", len(pods))
+}
+
+// createPod create a mock pod named name
+func (pg *PodGenerator) createPod(name string) (*v1.Pod, error) {
+	return pg.clientSet.CoreV1().Pods(defaultNamespace).Create(pg.mock.pod(name))
 }
 
 // finalize remove all mock pocs
 func (pg *PodGenerator) finalize() {
+	if pg.mock.Phantom || pg.targets != nil {
+		return
+	}
 	err := pg.clientSet.CoreV1().Pods(defaultNamespace).DeleteCollection(&metav1.DeleteOptions{}, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("gen-by=%s", kubernetesEventsGenerator),
+		LabelSelector: runSelector(pg.mock.RunID),
 	})
 
 	if err != nil {
@@ -321,7 +336,7 @@ func (pg *PodGenerator) finalize() {
 }
 
 // NewPodGenerator return new pod generator instance
-func NewPodGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int) *PodGenerator {
+func NewPodGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions, targets *Targets) *PodGenerator {
 	if seed <= minSeed {
 		seed = minSeed
 	}
@@ -329,5 +344,7 @@ func NewPodGenerator(clientSet kubernetes.Interface, recorder record.EventRecord
 		clientSet: clientSet,
 		recorder:  recorder,
 		seed:      seed,
+		mock:      mock,
+		targets:   targets,
 	}
 }
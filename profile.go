@@ -0,0 +1,171 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	constantProfile = "constant"
+	rampProfile     = "ramp"
+	stepProfile     = "step"
+	sineProfile     = "sine"
+	spikeProfile    = "spike"
+
+	// never wait longer than this between two events
+	minRate = 0.001
+)
+
+var (
+	loadProfile      = flag.String("load-profile", constantProfile, "event rate profile over time, one of constant, ramp, step, sine or spike")
+	baseRate         = flag.Float64("base-rate", 1.0/3, "events per second at the bottom of the load profile")
+	peakRate         = flag.Float64("peak-rate", 2, "events per second at the top of the ramp, step and sine profiles")
+	profilePeriod    = flag.Duration("profile-period", 24*time.Hour, "time the ramp takes to reach the peak rate, the step profile takes to climb all steps, or the sine wave takes to repeat")
+	profileSteps     = flag.Int("profile-steps", 4, "number of steps between base and peak rate of the step profile")
+	spikeMagnitude   = flag.Float64("spike-magnitude", 10, "base rate multiplier during a spike")
+	spikeProbability = flag.Float64("spike-probability", 0.01, "probability that a spike starts before an event")
+	spikeDuration    = flag.Duration("spike-duration", time.Minute, "how long a spike lasts")
+)
+
+// LoadProfile returns the event rate (events per second) after elapsed time
+type LoadProfile interface {
+	Rate(elapsed time.Duration) float64
+}
+
+// constant rate
+type constantLoadProfile struct {
+	rate float64
+}
+
+func (p *constantLoadProfile) Rate(elapsed time.Duration) float64 {
+	return p.rate
+}
+
+// linear ramp from base to peak over period, then hold the peak
+type rampLoadProfile struct {
+	base, peak float64
+	period     time.Duration
+}
+
+func (p *rampLoadProfile) Rate(elapsed time.Duration) float64 {
+	if elapsed >= p.period {
+		return p.peak
+	}
+	return p.base + (p.peak-p.base)*float64(elapsed)/float64(p.period)
+}
+
+// step function climbing from base to peak in steps over period, then start over
+type stepLoadProfile struct {
+	base, peak float64
+	period     time.Duration
+	steps      int
+}
+
+func (p *stepLoadProfile) Rate(elapsed time.Duration) float64 {
+	step := int(elapsed%p.period) * p.steps / int(p.period)
+	return p.base + (p.peak-p.base)*float64(step)/float64(p.steps-1)
+}
+
+// sine wave between base and peak, starting at base
+type sineLoadProfile struct {
+	base, peak float64
+	period     time.Duration
+}
+
+func (p *sineLoadProfile) Rate(elapsed time.Duration) float64 {
+	phase := 2 * math.Pi * float64(elapsed%p.period) / float64(p.period)
+	return p.base + (p.peak-p.base)*(1-math.Cos(phase))/2
+}
+
+// base rate with random spikes of magnitude times the base rate
+type spikeLoadProfile struct {
+	base      float64
+	magnitude float64
+	chance    float64
+	duration  time.Duration
+	spikeEnd  time.Duration
+}
+
+func (p *spikeLoadProfile) Rate(elapsed time.Duration) float64 {
+	if elapsed >= p.spikeEnd && randFloat64() < p.chance {
+		p.spikeEnd = elapsed + p.duration
+	}
+	if elapsed < p.spikeEnd {
+		return p.base * p.magnitude
+	}
+	return p.base
+}
+
+// newLoadProfile builds the load profile named by flags
+func newLoadProfile(name string) (LoadProfile, error) {
+	if *profilePeriod <= 0 {
+		return nil, fmt.Errorf("profile period must be positive, got %v", *profilePeriod)
+	}
+	switch name {
+	case constantProfile:
+		return &constantLoadProfile{rate: *baseRate}, nil
+	case rampProfile:
+		return &rampLoadProfile{base: *baseRate, peak: *peakRate, period: *profilePeriod}, nil
+	case stepProfile:
+		if *profileSteps < 2 {
+			return nil, fmt.Errorf("step profile needs at least 2 steps, got %d", *profileSteps)
+		}
+		return &stepLoadProfile{base: *baseRate, peak: *peakRate, period: *profilePeriod, steps: *profileSteps}, nil
+	case sineProfile:
+		return &sineLoadProfile{base: *baseRate, peak: *peakRate, period: *profilePeriod}, nil
+	case spikeProfile:
+		return &spikeLoadProfile{base: *baseRate, magnitude: *spikeMagnitude, chance: *spikeProbability, duration: *spikeDuration}, nil
+	}
+	return nil, fmt.Errorf("unknown load profile %q", name)
+}
+
+// Pacer spaces events of all generators according to a load profile
+type Pacer struct {
+	sync.Mutex
+	name    string
+	profile LoadProfile
+	start   time.Time
+	rate    float64
+	events  int64
+}
+
+// Wait blocks until the next event is due
+func (p *Pacer) Wait() {
+	p.Lock()
+	rate := p.profile.Rate(time.Since(p.start))
+	if rate < minRate {
+		rate = minRate
+	}
+	p.rate = rate
+	p.events++
+	p.Unlock()
+
+	time.Sleep(time.Duration(float64(time.Second) / rate))
+}
+
+// Stats returns the current rate and the number of paced events
+func (p *Pacer) Stats() (float64, int64) {
+	p.Lock()
+	defer p.Unlock()
+	return p.rate, p.events
+}
+
+// NewPacer return new pacer instance driven by profile
+func NewPacer(name string, profile LoadProfile) *Pacer {
+	return &Pacer{
+		name:    name,
+		profile: profile,
+		start:   time.Now(),
+		rate:    profile.Rate(0),
+	}
+}
+
+var pacer *Pacer
+
+// pace waits for the next event of the shared load profile
+func pace() {
+	pacer.Wait()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// same alphabet as k8s.io/apimachinery/pkg/util/rand, no vowels to avoid bad words
+const randAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+
+var (
+	randomSeed = flag.Int64("random-seed", 0, "seed of every random choice, runs with the same seed and flags emit the same event sequence; 0 picks a seed from the clock")
+)
+
+// random drives every random choice of the generators
+var random = struct {
+	sync.Mutex
+	rand *rand.Rand
+}{
+	rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+}
+
+// seedRandom reseeds the random source and returns the seed in use
+func seedRandom(seed int64) int64 {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	random.Lock()
+	defer random.Unlock()
+	random.rand = rand.New(rand.NewSource(seed))
+	return seed
+}
+
+// randString returns a random string of n lowercase alphanumeric characters
+func randString(n int) string {
+	random.Lock()
+	defer random.Unlock()
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = randAlphabet[random.rand.Intn(len(randAlphabet))]
+	}
+	return string(b)
+}
+
+// randIntn returns a random int in [0,n)
+func randIntn(n int) int {
+	random.Lock()
+	defer random.Unlock()
+	return random.rand.Intn(n)
+}
+
+// randFloat64 returns a random float64 in [0.0,1.0)
+func randFloat64() float64 {
+	random.Lock()
+	defer random.Unlock()
+	return random.rand.Float64()
+}
+
+// randBytes fills b with random bytes
+func randBytes(b []byte) {
+	random.Lock()
+	defer random.Unlock()
+	random.rand.Read(b)
+}
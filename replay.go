@@ -0,0 +1,269 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	replayGenerator = "replayGenerator"
+)
+
+var (
+	replayFile      = flag.String("replay-file", "", "file of events to replay, either `kubectl get events -o json` output or a stream of captured events")
+	replaySpeed     = flag.Float64("replay-speed", 1, "playback speed factor, 10 replays 10x faster and 0.1 10x slower")
+	replayNamespace = flag.String("replay-namespace", defaultNamespace, "namespace namespaced events are replayed in, empty keeps the captured namespace")
+)
+
+// kinds replayed events get mock involved objects for unless in phantom mode, other kinds are always phantom
+var replayMocks = map[string]func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error){
+	"Pod": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.CoreV1().Pods(namespace).Create(mock.pod(name))
+	},
+	"Deployment": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.AppsV1().Deployments(namespace).Create(mock.deployment(name))
+	},
+	"ReplicaSet": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.AppsV1().ReplicaSets(namespace).Create(mock.replicaSet(name))
+	},
+	"ReplicationController": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.CoreV1().ReplicationControllers(namespace).Create(mock.replicationController(name))
+	},
+	"StatefulSet": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.AppsV1().StatefulSets(namespace).Create(mock.statefulSet(name))
+	},
+	"DaemonSet": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.AppsV1().DaemonSets(namespace).Create(mock.daemonSet(name))
+	},
+	"Job": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.BatchV1().Jobs(namespace).Create(mock.job(name))
+	},
+	"CronJob": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.BatchV1beta1().CronJobs(namespace).Create(mock.cronJob(name))
+	},
+	"Service": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.CoreV1().Services(namespace).Create(mock.service(name))
+	},
+	"PersistentVolumeClaim": func(clientSet kubernetes.Interface, mock *MockOptions, namespace, name string) (runtime.Object, error) {
+		return clientSet.CoreV1().PersistentVolumeClaims(namespace).Create(mock.persistentVolumeClaim(name))
+	},
+}
+
+// a document in the replay file, either a list of events or a single event
+type replayDocument struct {
+	v1.Event `json:",inline"`
+	Items    []v1.Event `json:"items,omitempty"`
+}
+
+// replayObject is the object the events of one captured object are replayed on during a loop
+type replayObject struct {
+	object runtime.Object      // mock object or phantom reference events are recorded on
+	ref    *v1.ObjectReference // reference to object
+	mock   bool                // object was created and has to be deleted
+	events int                 // events replayed on object
+}
+
+// Replay events generator. Events are re-emitted on mock copies of their involved objects for the kinds
+// in replayMocks, on phantom copies for the other kinds or in phantom mode.
+type ReplayGenerator struct {
+	clientSet kubernetes.Interface
+	recorder  record.EventRecorder
+	mock      *MockOptions
+	file      string
+	speed     float64
+	namespace string
+	objects   map[string]*replayObject // copy of every captured object, new for every loop over the file
+}
+
+// Name returns the replay generator's name
+func (rg *ReplayGenerator) Name() string {
+	return replayGenerator
+}
+
+// Generate replay events
+func (rg *ReplayGenerator) Generate() {
+	rg.initialize()
+	defer rg.finalize()
+}
+
+// initialize load the captured events and re-emit them keeping their relative spacing
+func (rg *ReplayGenerator) initialize() {
+	// fresh copies every loop, the recorder would otherwise drop or merge events it saw on them before
+	rg.objects = map[string]*replayObject{}
+
+	events, err := loadEvents(rg.file)
+	if err != nil {
+		fmt.Printf("Failed to load events from %s,because of %v\n", rg.file, err)
+		return
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTimestamp(&events[i]).Before(eventTimestamp(&events[j]))
+	})
+
+	for i := range events {
+		event := &events[i]
+		if i > 0 {
+			gap := eventTimestamp(event).Sub(eventTimestamp(&events[i-1]))
+			time.Sleep(time.Duration(float64(gap) / rg.speed))
+		}
+		object := rg.involvedObject(event)
+		object.events++
+		if object.events <= maxEventsPerObject {
+			rg.recorder.Event(object.object, event.Type, event.Reason, event.Message)
+		} else {
+			// past the recorder's spam filter burst, keep the object and write the event directly
+			rg.writeEvent(object.ref, event)
+		}
+	}
+	fmt.Printf("Replay %d events on %d objects successfully.\n", len(events), len(rg.objects))
+}
+
+// finalize delete the mock objects of the loop
+func (rg *ReplayGenerator) finalize() {
+	for _, object := range rg.objects {
+		if !object.mock {
+			continue
+		}
+		kind := mockKindNamed(strings.ToLower(object.ref.Kind))
+		if kind == nil {
+			continue
+		}
+		if err := kind.delete(rg.clientSet, object.ref.Namespace, object.ref.Name); err != nil {
+			fmt.Printf("Failed to delete mock %s %s/%s,because of %v\n", kind.name, object.ref.Namespace, object.ref.Name, err)
+		}
+	}
+}
+
+// involvedObject returns the copy of the event's involved object, creating it on first use
+func (rg *ReplayGenerator) involvedObject(event *v1.Event) *replayObject {
+	captured := &event.InvolvedObject
+	key := string(captured.UID)
+	if key == "" {
+		key = fmt.Sprintf("%s/%s/%s/%s", captured.APIVersion, captured.Kind, captured.Namespace, captured.Name)
+	}
+	if object, ok := rg.objects[key]; ok {
+		return object
+	}
+
+	namespace := captured.Namespace
+	if namespace != "" && rg.namespace != "" {
+		namespace = rg.namespace
+	}
+	object := &replayObject{}
+	if create, ok := replayMocks[captured.Kind]; ok && namespace != "" && !rg.mock.Phantom {
+		created, err := create(rg.clientSet, rg.mock, namespace, captured.Name)
+		if err == nil {
+			object.ref, err = reference.GetReference(scheme.Scheme, created)
+		}
+		if err == nil {
+			object.object, object.mock = created, true
+		} else {
+			fmt.Printf("Failed to create mock %s %s/%s, replaying on a phantom one,because of %v\n", captured.Kind, namespace, captured.Name, err)
+		}
+	}
+	if object.object == nil {
+		ref := phantomReference(captured.Kind, captured.APIVersion, namespace, captured.Name)
+		ref.FieldPath = captured.FieldPath
+		object.object, object.ref = ref, ref
+	}
+	rg.objects[key] = object
+	return object
+}
+
+// writeEvent creates event on ref through the API the way the recorder would
+func (rg *ReplayGenerator) writeEvent(ref *v1.ObjectReference, event *v1.Event) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	now := metav1.Now()
+	_, err := rg.clientSet.CoreV1().Events(namespace).Create(&v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", ref.Name, now.UnixNano()),
+			Namespace: namespace,
+			Annotations: map[string]string{
+				runIDAnnotation: rg.mock.RunID,
+			},
+		},
+		InvolvedObject: *ref,
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Type:           event.Type,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Source:         v1.EventSource{Component: kubernetesEventsGenerator},
+	})
+	if err != nil {
+		fmt.Printf("Failed to write event on %s %s/%s,because of %v\n", ref.Kind, ref.Namespace, ref.Name, err)
+	}
+}
+
+// loadEvents reads all events in file, it accepts json or yaml lists and streams of events
+func loadEvents(file string) ([]v1.Event, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	events := []v1.Event{}
+	decoder := yaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		doc := replayDocument{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if len(doc.Items) > 0 {
+			events = append(events, doc.Items...)
+		} else if doc.Reason != "" {
+			events = append(events, doc.Event)
+		}
+	}
+	return events, nil
+}
+
+// eventTimestamp returns the time the event was last observed
+func eventTimestamp(event *v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// NewReplayGenerator return new replay generator instance
+func NewReplayGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, mock *MockOptions, file string, speed float64, namespace string) *ReplayGenerator {
+	if speed <= 0 {
+		speed = 1
+	}
+	return &ReplayGenerator{
+		clientSet: clientSet,
+		recorder:  recorder,
+		mock:      mock,
+		file:      file,
+		speed:     speed,
+		namespace: namespace,
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+)
+
+const (
+	replicaSetGenerator = "replicaSetGenerator"
+)
+
+var (
+	// admission rejections of pods, formatted with the generated name prefix of the pod
+	podAdmissionErrors = []string{
+		`pods "%s" is forbidden: exceeded quota: compute-resources, requested: pods=1, used: pods=10, limited: pods=10`,
+		`pods "%s" is forbidden: failed quota: compute-resources: must specify limits.cpu,limits.memory`,
+		`pods "%s" is forbidden: maximum cpu usage per Container is 2, but limit is 4`,
+		`pods "%s" is forbidden: unable to validate against any pod security policy: [spec.containers[0].securityContext.privileged: Invalid value: true: Privileged containers are not allowed]`,
+		`pods "%s" is forbidden: error looking up service account default/deployer: serviceaccount "deployer" not found`,
+	}
+)
+
+func init() {
+	registerMock("replicaset",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.AppsV1().ReplicaSets(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.AppsV1().ReplicaSets(namespace).Delete(name, deleteOptions())
+		})
+	registerMock("replicationcontroller",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.CoreV1().ReplicationControllers(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.CoreV1().ReplicationControllers(namespace).Delete(name, deleteOptions())
+		})
+}
+
+// replicaSetEvents returns the pod control events of replica set or replication controller name:
+// pods created, one deleted on scale down and a creation rejected by admission
+func replicaSetEvents(name string) []v1.Event {
+	podName := func() string {
+		return fmt.Sprintf("%s-%s", name, randString(5))
+	}
+	deleted := podName()
+	// rejected pods have no name yet, admission reports their generate name
+	rejected := fmt.Sprintf(podAdmissionErrors[randIntn(len(podAdmissionErrors))], name+"-")
+	return []v1.Event{
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  controller.SuccessfulCreatePodReason,
+			Message: fmt.Sprintf("Created pod: %s", podName()),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  controller.SuccessfulCreatePodReason,
+			Message: fmt.Sprintf("Created pod: %s", deleted),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  controller.FailedCreatePodReason,
+			Message: fmt.Sprintf("Error creating: %s", rejected),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  controller.SuccessfulDeletePodReason,
+			Message: fmt.Sprintf("Deleted pod: %s", deleted),
+		},
+	}
+}
+
+// ReplicaSetGenerator create events on mock replica sets and replication controllers
+type ReplicaSetGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many replica sets and replication controllers would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+}
+
+// Name returns the name of ReplicaSetGenerator
+func (rg *ReplicaSetGenerator) Name() string {
+	return replicaSetGenerator
+}
+
+// Generate create events on mock replica sets and replication controllers
+func (rg *ReplicaSetGenerator) Generate() {
+	rg.initialize()
+	defer rg.finalize()
+}
+
+// Mock and create several replica sets and replication controllers
+func (rg *ReplicaSetGenerator) initialize() {
+	for i := 0; i < rg.seed; i++ {
+		mockReplicaSetName := randString(15)
+		if replicaSet := rg.replicaSet(mockReplicaSetName); replicaSet != nil {
+			rg.emit(replicaSet, mockReplicaSetName)
+		}
+		mockControllerName := randString(15)
+		if rc := rg.replicationController(mockControllerName); rc != nil {
+			rg.emit(rc, mockControllerName)
+		}
+	}
+	fmt.Printf("Create %d replica sets and replication controllers successfully.\n", rg.seed)
+}
+
+// emit the events of object named name
+func (rg *ReplicaSetGenerator) emit(object runtime.Object, name string) {
+	for _, e := range replicaSetEvents(name) {
+		rg.recorder.Event(object, e.Type, e.Reason, e.Message)
+		pace()
+	}
+}
+
+// replicaSet returns a phantom or newly created replica set named name, nil if it cannot be created
+func (rg *ReplicaSetGenerator) replicaSet(name string) runtime.Object {
+	if rg.mock.Phantom {
+		return phantomReference("ReplicaSet", "apps/v1", defaultNamespace, name)
+	}
+	created, err := rg.clientSet.AppsV1().ReplicaSets(defaultNamespace).Create(rg.mock.replicaSet(name))
+	if err != nil {
+		fmt.Printf("Failed to create replica set because of %v\n", err)
+		return nil
+	}
+	return created
+}
+
+// replicationController returns a phantom or newly created replication controller named name, nil if it cannot be created
+func (rg *ReplicaSetGenerator) replicationController(name string) runtime.Object {
+	if rg.mock.Phantom {
+		return phantomReference("ReplicationController", "v1", defaultNamespace, name)
+	}
+	created, err := rg.clientSet.CoreV1().ReplicationControllers(defaultNamespace).Create(rg.mock.replicationController(name))
+	if err != nil {
+		fmt.Printf("Failed to create replication controller because of %v\n", err)
+		return nil
+	}
+	return created
+}
+
+// finalize all replica sets and replication controllers mocked
+func (rg *ReplicaSetGenerator) finalize() {
+	if rg.mock.Phantom {
+		return
+	}
+	options := metav1.ListOptions{
+		LabelSelector: runSelector(rg.mock.RunID),
+	}
+	if err := rg.clientSet.AppsV1().ReplicaSets(defaultNamespace).DeleteCollection(deleteOptions(), options); err != nil {
+		fmt.Printf("Failed to delete mock replica sets,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock replica sets successfully.\n")
+	}
+	if err := rg.clientSet.CoreV1().ReplicationControllers(defaultNamespace).DeleteCollection(deleteOptions(), options); err != nil {
+		fmt.Printf("Failed to delete mock replication controllers,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock replication controllers successfully.\n")
+	}
+}
+
+// NewReplicaSetGenerator return new replica set and replication controller generator instance
+func NewReplicaSetGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions) *ReplicaSetGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &ReplicaSetGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"sort"
+	"strings"
+)
+
+const (
+	schedulerGenerator = "schedulerGenerator"
+
+	// scheduler predicate failure reasons
+	nodeNotReadyReason      = "node(s) were not ready"
+	nodeUnschedulableReason = "node(s) were unschedulable"
+	nodeSelectorReason      = "node(s) didn't match node selector"
+	nodeTaintsReason        = "node(s) had taints that the pod didn't tolerate"
+)
+
+// schedulingPod is the part of a pod the scheduler emulation looks at
+type schedulingPod struct {
+	name         string
+	requests     v1.ResourceList
+	nodeSelector map[string]string
+	tolerations  []v1.Toleration
+}
+
+// newSchedulingPod returns the i-th pod of a scheduling round, pods take turns by index i: small pods
+// that fit most nodes, large pods that do not fit many, and pods selecting a label most nodes lack.
+// They tolerate the taint of synthetic nodes, a test pod meant for them would.
+func newSchedulingPod(i int) schedulingPod {
+	pod := schedulingPod{
+		name: randString(15),
+		tolerations: []v1.Toleration{
+			{
+				Key:      syntheticNodeTaintKey,
+				Operator: v1.TolerationOpExists,
+				Effect:   v1.TaintEffectNoSchedule,
+			},
+		},
+	}
+	switch i % 3 {
+	case 0:
+		pod.requests = v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("100m"),
+			v1.ResourceMemory: resource.MustParse("128Mi"),
+		}
+	case 1:
+		pod.requests = v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("3"),
+			v1.ResourceMemory: resource.MustParse("12Gi"),
+		}
+	default:
+		pod.requests = v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("500m"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+		pod.nodeSelector = map[string]string{
+			"disktype": "ssd",
+		}
+	}
+	return pod
+}
+
+// schedulingNode is a node with the resources its pods already use
+type schedulingNode struct {
+	node *v1.Node
+	used v1.ResourceList
+}
+
+// newSchedulingNodes returns nodes with a random share of their allocatable resources in use,
+// the emulation does not list the pods running on them
+func newSchedulingNodes(nodes []v1.Node) []schedulingNode {
+	schedulingNodes := make([]schedulingNode, 0, len(nodes))
+	for i := range nodes {
+		used := v1.ResourceList{}
+		for name, allocatable := range nodes[i].Status.Allocatable {
+			share := 0.2 + 0.7*randFloat64()
+			used[name] = *resource.NewMilliQuantity(int64(float64(allocatable.MilliValue())*share), allocatable.Format)
+		}
+		schedulingNodes = append(schedulingNodes, schedulingNode{node: &nodes[i], used: used})
+	}
+	return schedulingNodes
+}
+
+// fit returns why pod does not fit on node the way the 1.14 scheduler predicates do, nothing if it fits.
+// The first failing predicate wins, except for the general predicates that report all their reasons.
+func (sn *schedulingNode) fit(pod *schedulingPod) []string {
+	for _, condition := range sn.node.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status != v1.ConditionTrue {
+			return []string{nodeNotReadyReason}
+		}
+	}
+	if sn.node.Spec.Unschedulable {
+		return []string{nodeUnschedulableReason}
+	}
+
+	reasons := []string{}
+	// pods count as one pod against the pods resource
+	requests := pod.requests.DeepCopy()
+	requests[v1.ResourcePods] = resource.MustParse("1")
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods} {
+		allocatable, ok := sn.node.Status.Allocatable[name]
+		if !ok {
+			continue
+		}
+		request, used := requests[name], sn.used[name]
+		if request.MilliValue()+used.MilliValue() > allocatable.MilliValue() {
+			reasons = append(reasons, fmt.Sprintf("Insufficient %s", name))
+		}
+	}
+	for key, value := range pod.nodeSelector {
+		if sn.node.Labels[key] != value {
+			reasons = append(reasons, nodeSelectorReason)
+			break
+		}
+	}
+	if len(reasons) > 0 {
+		return reasons
+	}
+
+	for i := range sn.node.Spec.Taints {
+		taint := &sn.node.Spec.Taints[i]
+		if taint.Effect != v1.TaintEffectNoSchedule && taint.Effect != v1.TaintEffectNoExecute {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range pod.tolerations {
+			if toleration.ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return []string{nodeTaintsReason}
+		}
+	}
+	return nil
+}
+
+// preemptible tells whether evicting pods from the node would make room for pod,
+// that is when pod only lacks resources on the node but not more than it has
+func (sn *schedulingNode) preemptible(pod *schedulingPod, reasons []string) bool {
+	for _, reason := range reasons {
+		if !strings.HasPrefix(reason, "Insufficient ") {
+			return false
+		}
+	}
+	for name, request := range pod.requests {
+		if allocatable, ok := sn.node.Status.Allocatable[name]; ok && request.Cmp(allocatable) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// assume adds the requests of pod to the resources used on the node
+func (sn *schedulingNode) assume(pod *schedulingPod) {
+	for name, request := range pod.requests {
+		used := sn.used[name]
+		used.Add(request)
+		sn.used[name] = used
+	}
+}
+
+// fitError returns the scheduler's message on a pod fitting none of total nodes for reasons,
+// the scheduler has a message of its own when there are no nodes at all
+func fitError(total int, reasons map[string]int) string {
+	if total == 0 {
+		return "no nodes available to schedule pods"
+	}
+	histogram := []string{}
+	for reason, count := range reasons {
+		histogram = append(histogram, fmt.Sprintf("%d %s", count, reason))
+	}
+	sort.Strings(histogram)
+	return fmt.Sprintf("0/%d nodes are available: %s.", total, strings.Join(histogram, ", "))
+}
+
+// SchedulerGenerator emulates the scheduler on the node generator's nodes and emits its events on phantom pods
+type SchedulerGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many pods would be scheduled
+	recorder  record.EventRecorder
+	mock      *MockOptions
+	nodes     *NodeGenerator
+}
+
+// Name returns the name of SchedulerGenerator
+func (sg *SchedulerGenerator) Name() string {
+	return schedulerGenerator
+}
+
+// Generate create scheduler events
+func (sg *SchedulerGenerator) Generate() {
+	sg.initialize()
+	defer sg.finalize()
+}
+
+// Schedule several pods on the nodes and emit the outcome. The pods are phantom, a real pod
+// would be scheduled by the real scheduler.
+func (sg *SchedulerGenerator) initialize() {
+	nodes := newSchedulingNodes(sg.schedulingNodes())
+	for i := 0; i < sg.seed; i++ {
+		pod := newSchedulingPod(i)
+		ref := phantomReference("Pod", "v1", defaultNamespace, pod.name)
+
+		fitting := []*schedulingNode{}
+		preemptible := []*schedulingNode{}
+		reasons := map[string]int{}
+		for j := range nodes {
+			node := &nodes[j]
+			failures := node.fit(&pod)
+			if len(failures) == 0 {
+				fitting = append(fitting, node)
+				continue
+			}
+			if node.preemptible(&pod, failures) {
+				preemptible = append(preemptible, node)
+			}
+			for _, reason := range failures {
+				reasons[reason]++
+			}
+		}
+
+		if len(fitting) > 0 {
+			sg.scheduled(ref, &pod, fitting[randIntn(len(fitting))])
+			continue
+		}
+		sg.recorder.Event(ref, v1.EventTypeWarning, "FailedScheduling", fitError(len(nodes), reasons))
+		pace()
+		if len(preemptible) > 0 {
+			node := preemptible[randIntn(len(preemptible))]
+			victim := phantomReference("Pod", "v1", defaultNamespace, randString(15))
+			sg.recorder.Eventf(victim, v1.EventTypeNormal, "Preempted", "by %s/%s on node %s", defaultNamespace, pod.name, node.node.Name)
+			pace()
+			sg.scheduled(ref, &pod, node)
+		}
+	}
+	fmt.Printf("Schedule %d pods on %d nodes successfully.\n", sg.seed, len(nodes))
+}
+
+// scheduled emits ref being bound to node and accounts for the requests of pod on node
+func (sg *SchedulerGenerator) scheduled(ref *v1.ObjectReference, pod *schedulingPod, node *schedulingNode) {
+	node.assume(pod)
+	sg.recorder.Eventf(ref, v1.EventTypeNormal, "Scheduled", "Successfully assigned %s/%s to %s", ref.Namespace, ref.Name, node.node.Name)
+	pace()
+}
+
+// schedulingNodes returns the nodes to schedule on. Phantom nodes only have a name,
+// they get the capacity of synthetic nodes.
+func (sg *SchedulerGenerator) schedulingNodes() []v1.Node {
+	nodes := sg.nodes.nodes()
+	if !sg.mock.Phantom {
+		return nodes
+	}
+	phantomNodes := make([]v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		phantomNodes = append(phantomNodes, *sg.mock.node(node.Name))
+	}
+	return phantomNodes
+}
+
+// finalize nothing, scheduler events are emitted on phantom pods
+func (sg *SchedulerGenerator) finalize() {
+}
+
+// NewSchedulerGenerator return new scheduler generator instance, it schedules on the nodes of nodes
+func NewSchedulerGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions, nodes *NodeGenerator) *SchedulerGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &SchedulerGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+		nodes:     nodes,
+	}
+}
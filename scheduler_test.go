@@ -0,0 +1,137 @@
+package main
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"testing"
+)
+
+// testSchedulingNode returns a ready 4 cpu, 16Gi node named name using cpu and memory
+func testSchedulingNode(name, cpu, memory string) schedulingNode {
+	return schedulingNode{
+		node: &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"kubernetes.io/hostname": name},
+			},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("4"),
+					v1.ResourceMemory: resource.MustParse("16Gi"),
+					v1.ResourcePods:   resource.MustParse("110"),
+				},
+				Conditions: []v1.NodeCondition{
+					{Type: v1.NodeReady, Status: v1.ConditionTrue},
+				},
+			},
+		},
+		used: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+			v1.ResourcePods:   resource.MustParse("10"),
+		},
+	}
+}
+
+func testSchedulingPod(cpu, memory string, nodeSelector map[string]string) schedulingPod {
+	return schedulingPod{
+		name: "web",
+		requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		},
+		nodeSelector: nodeSelector,
+	}
+}
+
+func TestFit(t *testing.T) {
+	notReady := testSchedulingNode("not-ready", "0", "0")
+	notReady.node.Status.Conditions[0].Status = v1.ConditionFalse
+	unschedulable := testSchedulingNode("unschedulable", "0", "0")
+	unschedulable.node.Spec.Unschedulable = true
+	tainted := testSchedulingNode("tainted", "0", "0")
+	tainted.node.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+	tolerated := testSchedulingNode("tolerated", "0", "0")
+	tolerated.node.Spec.Taints = []v1.Taint{{Key: syntheticNodeTaintKey, Value: "true", Effect: v1.TaintEffectNoSchedule}}
+	preferred := testSchedulingNode("preferred", "0", "0")
+	preferred.node.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectPreferNoSchedule}}
+
+	synthetic := testSchedulingPod("100m", "128Mi", nil)
+	synthetic.tolerations = []v1.Toleration{{Key: syntheticNodeTaintKey, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule}}
+
+	tests := []struct {
+		name    string
+		node    schedulingNode
+		pod     schedulingPod
+		reasons []string
+	}{
+		{"fits", testSchedulingNode("idle", "1", "4Gi"), testSchedulingPod("100m", "128Mi", nil), nil},
+		{"exactly fits", testSchedulingNode("busy", "3", "12Gi"), testSchedulingPod("1", "4Gi", nil), nil},
+		{"insufficient cpu", testSchedulingNode("busy", "3500m", "4Gi"), testSchedulingPod("1", "1Gi", nil), []string{"Insufficient cpu"}},
+		{"insufficient cpu and memory", testSchedulingNode("busy", "3500m", "15Gi"), testSchedulingPod("1", "2Gi", nil), []string{"Insufficient cpu", "Insufficient memory"}},
+		{"resources and selector", testSchedulingNode("busy", "3500m", "4Gi"), testSchedulingPod("1", "1Gi", map[string]string{"disktype": "ssd"}), []string{"Insufficient cpu", nodeSelectorReason}},
+		{"selector", testSchedulingNode("idle", "0", "0"), testSchedulingPod("100m", "128Mi", map[string]string{"disktype": "ssd"}), []string{nodeSelectorReason}},
+		{"not ready wins", notReady, testSchedulingPod("100", "1Ti", nil), []string{nodeNotReadyReason}},
+		{"unschedulable", unschedulable, testSchedulingPod("100m", "128Mi", nil), []string{nodeUnschedulableReason}},
+		{"taint", tainted, testSchedulingPod("100m", "128Mi", nil), []string{nodeTaintsReason}},
+		{"tolerated taint", tolerated, synthetic, nil},
+		{"prefer no schedule taint", preferred, testSchedulingPod("100m", "128Mi", nil), nil},
+	}
+	for _, test := range tests {
+		if reasons := test.node.fit(&test.pod); !reflect.DeepEqual(reasons, test.reasons) {
+			t.Errorf("%s: got reasons %q, want %q", test.name, reasons, test.reasons)
+		}
+	}
+}
+
+func TestPreemptible(t *testing.T) {
+	tests := []struct {
+		name        string
+		pod         schedulingPod
+		reasons     []string
+		preemptible bool
+	}{
+		{"lacks resources", testSchedulingPod("1", "1Gi", nil), []string{"Insufficient cpu"}, true},
+		{"larger than the node", testSchedulingPod("8", "1Gi", nil), []string{"Insufficient cpu"}, false},
+		{"selector", testSchedulingPod("1", "1Gi", nil), []string{"Insufficient cpu", nodeSelectorReason}, false},
+		{"taint", testSchedulingPod("1", "1Gi", nil), []string{nodeTaintsReason}, false},
+	}
+	for _, test := range tests {
+		node := testSchedulingNode("busy", "3500m", "4Gi")
+		if preemptible := node.preemptible(&test.pod, test.reasons); preemptible != test.preemptible {
+			t.Errorf("%s: got preemptible %v, want %v", test.name, preemptible, test.preemptible)
+		}
+	}
+}
+
+func TestFitError(t *testing.T) {
+	tests := []struct {
+		total   int
+		reasons map[string]int
+		message string
+	}{
+		{12, map[string]int{nodeSelectorReason: 9, "Insufficient cpu": 3}, "0/12 nodes are available: 3 Insufficient cpu, 9 node(s) didn't match node selector."},
+		{3, map[string]int{nodeNotReadyReason: 1, "Insufficient cpu": 2, "Insufficient memory": 2}, "0/3 nodes are available: 1 node(s) were not ready, 2 Insufficient cpu, 2 Insufficient memory."},
+		{1, map[string]int{nodeTaintsReason: 1}, "0/1 nodes are available: 1 node(s) had taints that the pod didn't tolerate."},
+		{0, map[string]int{}, "no nodes available to schedule pods"},
+	}
+	for _, test := range tests {
+		if message := fitError(test.total, test.reasons); message != test.message {
+			t.Errorf("got message %q, want %q", message, test.message)
+		}
+	}
+}
+
+func TestAssume(t *testing.T) {
+	node := testSchedulingNode("idle", "3", "4Gi")
+	pod := testSchedulingPod("1", "1Gi", nil)
+	if reasons := node.fit(&pod); reasons != nil {
+		t.Fatalf("got reasons %q before assuming, want none", reasons)
+	}
+	node.assume(&pod)
+	if reasons := node.fit(&pod); !reflect.DeepEqual(reasons, []string{"Insufficient cpu"}) {
+		t.Errorf("got reasons %q after assuming, want Insufficient cpu", reasons)
+	}
+}
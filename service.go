@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"strings"
+)
+
+const (
+	serviceGenerator = "serviceGenerator"
+)
+
+var (
+	// errors of cloud providers ensuring load balancers, formatted with the load balancer name and the service namespace/name
+	loadBalancerErrors = []string{
+		`failed to ensure a static IP for load balancer (%s(%s)): googleapi: Error 403: QUOTA_EXCEEDED - Quota 'STATIC_ADDRESSES' exceeded. Limit: 8.0 in region us-central1., quotaExceeded`,
+		`could not find any suitable subnets for creating the ELB %s for service %s`,
+		`ensure(%[2]s): lb(%[1]s) - failed to ensure host in pool: "instance not found"`,
+		`[SDK.ServerError] ErrorCode: Throttling.User Recommend: Request was denied due to user flow control. Message: load balancer %s of service %s not ready`,
+	}
+	// errors of cloud providers updating the hosts of load balancers, formatted with the load balancer name
+	loadBalancerUpdateErrors = []string{
+		`googleapi: Error 400: Invalid value for field 'resource.instances[0]': The instance is not in the same zone as the target pool %s., invalid`,
+		`error registering instances with the load balancer %s: "Throttling: Rate exceeded, status code: 400"`,
+		`lb(%s) - failed to ensure host in pool: "instance not found"`,
+	}
+)
+
+func init() {
+	registerMock("service",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.CoreV1().Services(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.CoreV1().Services(namespace).Delete(name, deleteOptions())
+		})
+}
+
+// loadBalancerName returns the name cloud providers give the load balancer of the service with uid
+func loadBalancerName(uid types.UID) string {
+	name := "a" + strings.Replace(string(uid), "-", "", -1)
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return name
+}
+
+// serviceEvents returns the service controller's load balancer lifecycle events of service name
+// whose load balancer is lb and balances over nodes
+func serviceEvents(name, lb string, nodes []v1.Node) []v1.Event {
+	service := fmt.Sprintf("%s/%s", defaultNamespace, name)
+	// the controller prints the set of host names
+	hosts := []string{}
+	for _, node := range nodes {
+		hosts = append(hosts, node.Name+":{}")
+	}
+	return []v1.Event{
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "EnsuringLoadBalancer",
+			Message: "Ensuring load balancer",
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "SyncLoadBalancerFailed",
+			Message: "Error syncing load balancer: failed to ensure load balancer: " + fmt.Sprintf(loadBalancerErrors[randIntn(len(loadBalancerErrors))], lb, service),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "EnsuringLoadBalancer",
+			Message: "Ensuring load balancer",
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "EnsuredLoadBalancer",
+			Message: "Ensured load balancer",
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "UpdatedLoadBalancer",
+			Message: "Updated load balancer with new hosts",
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  "LoadBalancerUpdateFailed",
+			Message: fmt.Sprintf("Error updating load balancer with new hosts map[%s]: ", strings.Join(hosts, " ")) + fmt.Sprintf(loadBalancerUpdateErrors[randIntn(len(loadBalancerUpdateErrors))], lb),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "DeletingLoadBalancer",
+			Message: "Deleting load balancer",
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  "DeletedLoadBalancer",
+			Message: "Deleted load balancer",
+		},
+	}
+}
+
+// ServiceGenerator create load balancer events on mock services, they balance over the nodes of the node generator
+type ServiceGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many services would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+	nodes     *NodeGenerator
+}
+
+// Name returns the name of ServiceGenerator
+func (sg *ServiceGenerator) Name() string {
+	return serviceGenerator
+}
+
+// Generate create events on mock services
+func (sg *ServiceGenerator) Generate() {
+	sg.initialize()
+	defer sg.finalize()
+}
+
+// Mock and create several services
+func (sg *ServiceGenerator) initialize() {
+	nodes := sg.nodes.nodes()
+	for i := 0; i < sg.seed; i++ {
+		mockServiceName := randString(15)
+		var service runtime.Object
+		var uid types.UID
+		if sg.mock.Phantom {
+			ref := phantomReference("Service", "v1", defaultNamespace, mockServiceName)
+			service, uid = ref, ref.UID
+		} else {
+			created, err := sg.clientSet.CoreV1().Services(defaultNamespace).Create(sg.mock.service(mockServiceName))
+			if err != nil {
+				fmt.Printf("Failed to create service because of %v\n", err)
+				continue
+			}
+			service, uid = created, created.UID
+		}
+
+		for _, e := range serviceEvents(mockServiceName, loadBalancerName(uid), nodes) {
+			sg.recorder.Event(service, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create %d services successfully.\n", sg.seed)
+}
+
+// finalize all services mocked, the services API does not support deleting collections
+func (sg *ServiceGenerator) finalize() {
+	if sg.mock.Phantom {
+		return
+	}
+	serviceList, err := sg.clientSet.CoreV1().Services(defaultNamespace).List(metav1.ListOptions{
+		LabelSelector: runSelector(sg.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to list mock services,because of %v\n", err)
+		return
+	}
+	for _, service := range serviceList.Items {
+		if err := sg.clientSet.CoreV1().Services(defaultNamespace).Delete(service.Name, deleteOptions()); err != nil {
+			fmt.Printf("Failed to delete mock service %s,because of %v\n", service.Name, err)
+		}
+	}
+	fmt.Print("Delete mock services successfully.\n")
+}
+
+// NewServiceGenerator return new service generator instance, its events name the nodes of nodes
+func NewServiceGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions, nodes *NodeGenerator) *ServiceGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &ServiceGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+		nodes:     nodes,
+	}
+}
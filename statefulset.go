@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+)
+
+const (
+	statefulSetGenerator = "statefulSetGenerator"
+	// claim template of mock stateful sets
+	statefulSetClaimTemplate = "data"
+	// ordinals the stateful set events are about
+	statefulSetOrdinals = 3
+)
+
+func init() {
+	registerMock("statefulset",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.AppsV1().StatefulSets(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.AppsV1().StatefulSets(namespace).Delete(name, deleteOptions())
+		})
+}
+
+// statefulSetEvents returns the events the stateful set controller emits while scaling up
+// stateful set name, recreating a failed pod and scaling down again
+func statefulSetEvents(name string) []v1.Event {
+	podName := func(ordinal int) string {
+		return fmt.Sprintf("%s-%d", name, ordinal)
+	}
+	claimName := func(ordinal int) string {
+		return fmt.Sprintf("%s-%s", statefulSetClaimTemplate, podName(ordinal))
+	}
+
+	events := []v1.Event{}
+	for ordinal := 0; ordinal < statefulSetOrdinals; ordinal++ {
+		events = append(events,
+			v1.Event{
+				Type:    v1.EventTypeNormal,
+				Reason:  controller.SuccessfulCreatePodReason,
+				Message: fmt.Sprintf("create Claim %s Pod %s in StatefulSet %s success", claimName(ordinal), podName(ordinal), name),
+			},
+			v1.Event{
+				Type:    v1.EventTypeNormal,
+				Reason:  controller.SuccessfulCreatePodReason,
+				Message: fmt.Sprintf("create Pod %s in StatefulSet %s successful", podName(ordinal), name),
+			},
+		)
+	}
+	last := statefulSetOrdinals
+	return append(events,
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  controller.FailedCreatePodReason,
+			Message: fmt.Sprintf("create Claim %s for Pod %s in StatefulSet %s failed error: persistentvolumeclaims %q is forbidden: exceeded quota: storage-quota, requested: requests.storage=10Gi, used: requests.storage=30Gi, limited: requests.storage=30Gi", claimName(last), podName(last), name, claimName(last)),
+		},
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  controller.FailedCreatePodReason,
+			Message: fmt.Sprintf("create Pod %s in StatefulSet %s failed error: Failed to create PVC %s: persistentvolumeclaims %q is forbidden: exceeded quota: storage-quota", podName(last), name, claimName(last), claimName(last)),
+		},
+		v1.Event{
+			Type:    v1.EventTypeWarning,
+			Reason:  "RecreatingFailedPod",
+			Message: fmt.Sprintf("StatefulSet %s/%s is recreating failed Pod %s", defaultNamespace, name, podName(1)),
+		},
+		v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  controller.SuccessfulDeletePodReason,
+			Message: fmt.Sprintf("delete Pod %s in StatefulSet %s successful", podName(1), name),
+		},
+		v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  controller.SuccessfulCreatePodReason,
+			Message: fmt.Sprintf("create Pod %s in StatefulSet %s successful", podName(1), name),
+		},
+		v1.Event{
+			Type:    v1.EventTypeNormal,
+			Reason:  controller.SuccessfulDeletePodReason,
+			Message: fmt.Sprintf("delete Pod %s in StatefulSet %s successful", podName(statefulSetOrdinals-1), name),
+		},
+	)
+}
+
+// StatefulSetGenerator create events on mock stateful sets
+type StatefulSetGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many stateful sets would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+}
+
+// Name returns the name of StatefulSetGenerator
+func (sg *StatefulSetGenerator) Name() string {
+	return statefulSetGenerator
+}
+
+// Generate create events on mock stateful sets
+func (sg *StatefulSetGenerator) Generate() {
+	sg.initialize()
+	defer sg.finalize()
+}
+
+// Mock and create several stateful sets
+func (sg *StatefulSetGenerator) initialize() {
+	for i := 0; i < sg.seed; i++ {
+		mockStatefulSetName := randString(15)
+		var statefulSet runtime.Object
+		if sg.mock.Phantom {
+			statefulSet = phantomReference("StatefulSet", "apps/v1", defaultNamespace, mockStatefulSetName)
+		} else {
+			created, err := sg.clientSet.AppsV1().StatefulSets(defaultNamespace).Create(sg.mock.statefulSet(mockStatefulSetName))
+			if err != nil {
+				fmt.Printf("Failed to create stateful set because of %v\n", err)
+				continue
+			}
+			statefulSet = created
+		}
+
+		for _, e := range statefulSetEvents(mockStatefulSetName) {
+			sg.recorder.Event(statefulSet, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create %d stateful sets successfully.\n", sg.seed)
+}
+
+// finalize all stateful sets mocked
+func (sg *StatefulSetGenerator) finalize() {
+	if sg.mock.Phantom {
+		return
+	}
+	err := sg.clientSet.AppsV1().StatefulSets(defaultNamespace).DeleteCollection(deleteOptions(), metav1.ListOptions{
+		LabelSelector: runSelector(sg.mock.RunID),
+	})
+	if err != nil {
+		fmt.Printf("Failed to delete mock stateful sets,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock stateful sets successfully.\n")
+	}
+}
+
+// NewStatefulSetGenerator return new stateful set generator instance
+func NewStatefulSetGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions) *StatefulSetGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &StatefulSetGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/kubernetes/pkg/kubelet/events"
+)
+
+var (
+	patchPodStatus = flag.Bool("patch-pod-status", false, "patch the container statuses of mock pods to match the emitted pod events, best used with --inert so no kubelet overwrites them")
+)
+
+// podStatusKey identifies a pod event, kubelet reuses reasons like BackOff and Failed
+type podStatusKey struct {
+	reason  string
+	message string
+}
+
+// pod events and the container status kubelet reports along with them
+var podStatusChanges = map[podStatusKey]func(status *v1.ContainerStatus){
+	{events.PullingImage, "pulling image"}: func(status *v1.ContainerStatus) {
+		status.State = waitingState("ContainerCreating", "")
+	},
+	{events.FailedToPullImage, "Failed to pull image"}: func(status *v1.ContainerStatus) {
+		status.State = waitingState("ErrImagePull", fmt.Sprintf("rpc error: code = Unknown desc = Error response from daemon: pull access denied for %s", status.Image))
+	},
+	{events.BackOffPullImage, "Back-off pulling image"}: func(status *v1.ContainerStatus) {
+		status.State = waitingState("ImagePullBackOff", fmt.Sprintf("Back-off pulling image %q", status.Image))
+	},
+	{events.ErrImageNeverPullPolicy, "Container image is not present with pull policy of Never"}: func(status *v1.ContainerStatus) {
+		status.State = waitingState("ErrImageNeverPull", fmt.Sprintf("Container image %q is not present with pull policy of Never", status.Image))
+	},
+	{events.FailedToCreateContainer, "FailedToCreateContainer"}: func(status *v1.ContainerStatus) {
+		status.State = waitingState("CreateContainerError", "failed to create container")
+	},
+	{events.StartedContainer, "StartedContainer"}: func(status *v1.ContainerStatus) {
+		status.State = v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.Now()}}
+		status.Ready = true
+	},
+	{events.ContainerUnhealthy, "probe errored"}: func(status *v1.ContainerStatus) {
+		status.Ready = false
+	},
+	{events.BackOffStartContainer, "Back-off restarting failed container"}: func(status *v1.ContainerStatus) {
+		now := metav1.Now()
+		status.LastTerminationState = v1.ContainerState{
+			Terminated: &v1.ContainerStateTerminated{
+				ExitCode:   1,
+				Reason:     "Error",
+				FinishedAt: now,
+			},
+		}
+		status.State = waitingState("CrashLoopBackOff", fmt.Sprintf("Back-off 5m0s restarting failed container=%s", status.Name))
+		status.Ready = false
+		status.RestartCount++
+	},
+}
+
+func waitingState(reason, message string) v1.ContainerState {
+	return v1.ContainerState{
+		Waiting: &v1.ContainerStateWaiting{
+			Reason:  reason,
+			Message: message,
+		},
+	}
+}
+
+// patchPodStatus updates the container statuses of pod to match event, if kubelet would change them.
+// The scheduler and kubelet update the pod too, the change is applied to a fresh copy and retried on conflicts.
+// It returns the updated pod, or pod itself if nothing changed.
+func (pg *PodGenerator) patchPodStatus(pod *v1.Pod, event *v1.Event) *v1.Pod {
+	change, ok := podStatusChanges[podStatusKey{event.Reason, event.Message}]
+	if !ok {
+		return pod
+	}
+
+	pods := pg.clientSet.CoreV1().Pods(pod.Namespace)
+	var updated *v1.Pod
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := pods.Get(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		changeContainerStatuses(current, change)
+		updated, err = pods.UpdateStatus(current)
+		return err
+	})
+	if err != nil {
+		fmt.Printf("Failed to update status of pod %s,because of %v\n", pod.Name, err)
+		return pod
+	}
+	return updated
+}
+
+// changeContainerStatuses applies change to the status of every container of pod, adding missing statuses
+func changeContainerStatuses(pod *v1.Pod, change func(status *v1.ContainerStatus)) {
+	for _, container := range pod.Spec.Containers {
+		var status *v1.ContainerStatus
+		for i := range pod.Status.ContainerStatuses {
+			if pod.Status.ContainerStatuses[i].Name == container.Name {
+				status = &pod.Status.ContainerStatuses[i]
+			}
+		}
+		if status == nil {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+				Name:  container.Name,
+				Image: container.Image,
+			})
+			status = &pod.Status.ContainerStatuses[len(pod.Status.ContainerStatuses)-1]
+		}
+		change(status)
+	}
+}
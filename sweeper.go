@@ -0,0 +1,165 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"time"
+)
+
+const (
+	ttlAnnotation = "kubernetes-events-generator/ttl"
+)
+
+var (
+	objectTTL      = flag.Duration("object-ttl", time.Hour, "ttl annotated on mock objects, the sweeper removes objects of runs without a lease once expired; 0 disables the annotation")
+	sweepInterval  = flag.Duration("sweep-interval", 5*time.Minute, "how often to sweep mock objects orphaned by crashed runs; 0 disables the sweeper")
+	leaseNamespace = flag.String("lease-namespace", defaultNamespace, "namespace of the lease every run renews as heartbeat")
+	leaseDuration  = flag.Duration("lease-duration", time.Minute, "a run whose lease wasn't renewed for this long is considered dead")
+)
+
+func init() {
+	registerMock("lease",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.CoordinationV1().Leases(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.CoordinationV1().Leases(namespace).Delete(name, &metav1.DeleteOptions{})
+		})
+}
+
+// leaseName returns the name of the heartbeat lease of run
+func leaseName(runID string) string {
+	return fmt.Sprintf("%s-%s", kubernetesEventsGenerator, runID)
+}
+
+// renewLease creates or renews the heartbeat lease of the run
+func renewLease(clientSet kubernetes.Interface, mock *MockOptions) error {
+	leases := clientSet.CoordinationV1().Leases(*leaseNamespace)
+	now := metav1.NowMicro()
+	lease, err := leases.Get(leaseName(mock.RunID), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		holder := mock.RunID
+		seconds := int32(leaseDuration.Seconds())
+		_, err = leases.Create(&coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   leaseName(mock.RunID),
+				Labels: mock.mockLabels(),
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &seconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(lease)
+	return err
+}
+
+// heartbeat renews the lease of the run until the process exits
+func heartbeat(clientSet kubernetes.Interface, mock *MockOptions) {
+	for {
+		time.Sleep(*leaseDuration / 3)
+		if err := renewLease(clientSet, mock); err != nil {
+			fmt.Printf("Failed to renew lease of run %s,because of %v\n", mock.RunID, err)
+		}
+	}
+}
+
+// runLeases returns whether the lease of each run holding one is still renewed
+func runLeases(clientSet kubernetes.Interface) (map[string]bool, error) {
+	leaseList, err := clientSet.CoordinationV1().Leases(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: generatorSelector(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	alive := map[string]bool{}
+	for _, lease := range leaseList.Items {
+		runID := lease.Labels[runIDLabel]
+		alive[runID] = alive[runID] || leaseRenewed(&lease)
+	}
+	return alive, nil
+}
+
+// leaseRenewed tells whether lease was renewed within its duration
+func leaseRenewed(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return time.Now().Before(expiry)
+}
+
+// orphanReason returns why object is orphaned, or "" if its run still owns it. Objects of live runs
+// are never orphaned however long a pass takes. The ttl only matters for runs without a lease,
+// e.g. when it could not be created, it is a backstop rather than a deadline.
+func orphanReason(object metav1.Object, leases map[string]bool) string {
+	runID := object.GetLabels()[runIDLabel]
+	alive, leased := leases[runID]
+	if alive {
+		return ""
+	}
+	if leased {
+		return fmt.Sprintf("run %q has no heartbeat", runID)
+	}
+	// without a ttl there is no telling a dead run from one that could not create its lease
+	ttl, err := time.ParseDuration(object.GetAnnotations()[ttlAnnotation])
+	if err != nil || ttl <= 0 {
+		return ""
+	}
+	if time.Since(object.GetCreationTimestamp().Time) > ttl {
+		return fmt.Sprintf("run %q has no lease and ttl %v expired", runID, ttl)
+	}
+	return ""
+}
+
+// sweepOrphans removes mock objects left behind by dead runs
+func sweepOrphans(clientSet kubernetes.Interface) {
+	leases, err := runLeases(clientSet)
+	if err != nil {
+		fmt.Printf("Failed to list run leases,because of %v\n", err)
+		return
+	}
+
+	removed := 0
+	for _, kind := range mockKinds {
+		objects, err := kind.list(clientSet, metav1.ListOptions{LabelSelector: generatorSelector()})
+		if err != nil {
+			fmt.Printf("Failed to list mock %s,because of %v\n", kind.name, err)
+			continue
+		}
+		for _, object := range objects {
+			reason := orphanReason(object, leases)
+			if reason == "" {
+				continue
+			}
+			if err := kind.delete(clientSet, object.GetNamespace(), object.GetName()); err != nil && !errors.IsNotFound(err) {
+				fmt.Printf("Failed to delete orphaned %s %s/%s,because of %v\n", kind.name, object.GetNamespace(), object.GetName(), err)
+				continue
+			}
+			fmt.Printf("Delete orphaned %s %s/%s successfully, %s.\n", kind.name, object.GetNamespace(), object.GetName(), reason)
+			removed++
+		}
+	}
+	fmt.Printf("Sweep removed %d orphaned mock objects.\n", removed)
+}
+
+// runSweeper sweeps orphans every interval
+func runSweeper(clientSet kubernetes.Interface, interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		sweepOrphans(clientSet)
+	}
+}
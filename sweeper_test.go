@@ -0,0 +1,45 @@
+package main
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestOrphanReason(t *testing.T) {
+	leases := map[string]bool{
+		"alive": true,
+		"dead":  false,
+	}
+	object := func(runID, ttl string, age time.Duration) metav1.Object {
+		meta := &metav1.ObjectMeta{
+			Labels:            map[string]string{runIDLabel: runID},
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		}
+		if ttl != "" {
+			meta.Annotations = map[string]string{ttlAnnotation: ttl}
+		}
+		return meta
+	}
+
+	tests := []struct {
+		name     string
+		object   metav1.Object
+		orphaned bool
+	}{
+		{"live lease", object("alive", "", time.Minute), false},
+		{"live lease past ttl", object("alive", "1h", 2*time.Hour), false},
+		{"expired lease", object("dead", "", time.Minute), true},
+		{"expired lease within ttl", object("dead", "1h", time.Minute), true},
+		{"no lease within ttl", object("unknown", "1h", time.Minute), false},
+		{"no lease past ttl", object("unknown", "1h", 2*time.Hour), true},
+		{"no lease without ttl", object("unknown", "", 2*time.Hour), false},
+		{"no lease with invalid ttl", object("unknown", "forever", 2*time.Hour), false},
+		{"no lease with zero ttl", object("unknown", "0s", 2*time.Hour), false},
+	}
+	for _, test := range tests {
+		if reason := orphanReason(test.object, leases); (reason != "") != test.orphaned {
+			t.Errorf("%s: got reason %q, want orphaned %v", test.name, reason, test.orphaned)
+		}
+	}
+}
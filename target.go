@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sort"
+)
+
+var (
+	targetExisting                = flag.Bool("target-existing", false, "emit pod and deployment events on existing objects matching the target selectors instead of creating mocks, targets are never modified")
+	targetNamespace               = flag.String("target-namespace", metav1.NamespaceAll, "namespace of the targeted pods and deployments, empty targets all namespaces and requires pod and deployment selectors")
+	targetPodSelector             = flag.String("target-pod-selector", "", "label selector of the targeted pods")
+	targetPodFieldSelector        = flag.String("target-pod-field-selector", "", "field selector of the targeted pods")
+	targetDeploymentSelector      = flag.String("target-deployment-selector", "", "label selector of the targeted deployments")
+	targetDeploymentFieldSelector = flag.String("target-deployment-field-selector", "", "field selector of the targeted deployments")
+)
+
+// Targets caches the existing objects events are attached to
+type Targets struct {
+	pods        cache.SharedIndexInformer
+	deployments cache.SharedIndexInformer
+}
+
+// Pods returns the targeted pods sorted by namespace and name
+func (t *Targets) Pods() []*v1.Pod {
+	waitForSync(t.pods)
+	pods := []*v1.Pod{}
+	for _, obj := range t.pods.GetStore().List() {
+		pods = append(pods, obj.(*v1.Pod))
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Namespace+"/"+pods[i].Name < pods[j].Namespace+"/"+pods[j].Name
+	})
+	return pods
+}
+
+// Deployments returns the targeted deployments sorted by namespace and name
+func (t *Targets) Deployments() []*appsv1.Deployment {
+	waitForSync(t.deployments)
+	deployments := []*appsv1.Deployment{}
+	for _, obj := range t.deployments.GetStore().List() {
+		deployments = append(deployments, obj.(*appsv1.Deployment))
+	}
+	sort.Slice(deployments, func(i, j int) bool {
+		return deployments[i].Namespace+"/"+deployments[i].Name < deployments[j].Namespace+"/"+deployments[j].Name
+	})
+	return deployments
+}
+
+// validateSelectors returns an error if the label or field selector doesn't parse
+func validateSelectors(labelSelector, fieldSelector string) error {
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return fmt.Errorf("invalid label selector %q: %v", labelSelector, err)
+	}
+	if _, err := fields.ParseSelector(fieldSelector); err != nil {
+		return fmt.Errorf("invalid field selector %q: %v", fieldSelector, err)
+	}
+	return nil
+}
+
+// NewTargets return targets configured by flags, or nil if existing objects aren't targeted
+func NewTargets(clientSet kubernetes.Interface) (*Targets, error) {
+	if !*targetExisting {
+		return nil, nil
+	}
+	// unscoped targets would be every pod and deployment of the cluster, kube-system included
+	if *targetNamespace == metav1.NamespaceAll {
+		if *targetPodSelector == "" && *targetPodFieldSelector == "" {
+			return nil, fmt.Errorf("--target-existing needs --target-namespace or a pod selector, it would target every pod of the cluster")
+		}
+		if *targetDeploymentSelector == "" && *targetDeploymentFieldSelector == "" {
+			return nil, fmt.Errorf("--target-existing needs --target-namespace or a deployment selector, it would target every deployment of the cluster")
+		}
+	}
+	if err := validateSelectors(*targetPodSelector, *targetPodFieldSelector); err != nil {
+		return nil, err
+	}
+	if err := validateSelectors(*targetDeploymentSelector, *targetDeploymentFieldSelector); err != nil {
+		return nil, err
+	}
+	return &Targets{
+		pods:        startInformer(clientSet.CoreV1().RESTClient(), "pods", *targetNamespace, &v1.Pod{}, *targetPodSelector, *targetPodFieldSelector),
+		deployments: startInformer(clientSet.AppsV1().RESTClient(), "deployments", *targetNamespace, &appsv1.Deployment{}, *targetDeploymentSelector, *targetDeploymentFieldSelector),
+	}, nil
+}
@@ -1,8 +1,22 @@
 package main
 
+import (
+	"fmt"
+	"k8s.io/apimachinery/pkg/types"
+)
+
 const (
 	kubernetesEventsGenerator = "kuberenetes-events-generator"
 )
 
 // replicas of deployment
 func int32Ptr(i int32) *int32 { return &i }
+
+// newUID returns a random version 4 uuid for phantom objects
+func newUID() types.UID {
+	b := make([]byte, 16)
+	randBytes(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return types.UID(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]))
+}
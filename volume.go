@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	volumeGenerator = "volumeGenerator"
+	// storage class of mock claims, no cluster has it
+	missingStorageClass = "kubernetes-events-generator-none"
+	// provisioner the claims wait for
+	externalProvisioner = "ebs.csi.aws.com"
+
+	// reasons of the persistent volume controller and external provisioners,
+	// pkg/controller/volume/events is not vendored
+	provisioningFailedReason    = "ProvisioningFailed"
+	provisioningSucceededReason = "ProvisioningSucceeded"
+	externalProvisioningReason  = "ExternalProvisioning"
+	provisioningReason          = "Provisioning"
+	failedBindingReason         = "FailedBinding"
+	volumeMismatchReason        = "VolumeMismatch"
+	volumeFailedRecycleReason   = "VolumeFailedRecycle"
+	volumeFailedDeleteReason    = "VolumeFailedDelete"
+)
+
+func init() {
+	registerMock("persistentvolumeclaim",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.CoreV1().PersistentVolumeClaims(metav1.NamespaceAll).List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.CoreV1().PersistentVolumeClaims(namespace).Delete(name, deleteOptions())
+		})
+	registerMock("persistentvolume",
+		func(clientSet kubernetes.Interface, options metav1.ListOptions) (runtime.Object, error) {
+			return clientSet.CoreV1().PersistentVolumes().List(options)
+		},
+		func(clientSet kubernetes.Interface, namespace, name string) error {
+			return clientSet.CoreV1().PersistentVolumes().Delete(name, deleteOptions())
+		})
+}
+
+// claimEvents returns the provisioning events of claim name, provisioned as volume
+func claimEvents(name, volume string) []v1.Event {
+	return []v1.Event{
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  externalProvisioningReason,
+			Message: fmt.Sprintf("waiting for a volume to be created, either by external provisioner %q or manually created by system administrator", externalProvisioner),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  provisioningReason,
+			Message: fmt.Sprintf("External provisioner is provisioning volume for claim \"%s/%s\"", defaultNamespace, name),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  provisioningFailedReason,
+			Message: fmt.Sprintf("failed to provision volume with StorageClass %q: rpc error: code = DeadlineExceeded desc = context deadline exceeded", missingStorageClass),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  provisioningFailedReason,
+			Message: fmt.Sprintf("storageclass.storage.k8s.io %q not found", missingStorageClass),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  failedBindingReason,
+			Message: "waiting for first consumer to be created before binding",
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  volumeMismatchReason,
+			Message: fmt.Sprintf("Cannot bind to requested volume %q: storageClassName does not match", volume),
+		},
+		{
+			Type:    v1.EventTypeNormal,
+			Reason:  provisioningSucceededReason,
+			Message: fmt.Sprintf("Successfully provisioned volume %s", volume),
+		},
+	}
+}
+
+// volumeEvents returns the reclaim failures of volume name once its claim is gone
+func volumeEvents(name string) []v1.Event {
+	return []v1.Event{
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  volumeFailedRecycleReason,
+			Message: "No recycler plugin found for the volume!",
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  volumeFailedDeleteReason,
+			Message: fmt.Sprintf("Error getting deleter volume plugin for volume %q: no deletable volume plugin matched", name),
+		},
+		{
+			Type:    v1.EventTypeWarning,
+			Reason:  volumeFailedDeleteReason,
+			Message: fmt.Sprintf("rpc error: code = Internal desc = Could not delete volume %q: VolumeInUse: Volume vol-%s is currently attached to i-%s", name, randString(17), randString(17)),
+		},
+	}
+}
+
+// VolumeGenerator create provisioning events on mock persistent volume claims and volumes
+type VolumeGenerator struct {
+	clientSet kubernetes.Interface
+	seed      int // how many claims and volumes would be mocked
+	recorder  record.EventRecorder
+	mock      *MockOptions
+}
+
+// Name returns the name of VolumeGenerator
+func (vg *VolumeGenerator) Name() string {
+	return volumeGenerator
+}
+
+// Generate create events on mock persistent volume claims and volumes
+func (vg *VolumeGenerator) Generate() {
+	vg.initialize()
+	defer vg.finalize()
+}
+
+// Mock and create several claims, each with the volume it is provisioned as
+func (vg *VolumeGenerator) initialize() {
+	for i := 0; i < vg.seed; i++ {
+		mockClaimName := randString(15)
+		var claim, volume runtime.Object
+		var volumeName string
+		if vg.mock.Phantom {
+			ref := phantomReference("PersistentVolumeClaim", "v1", defaultNamespace, mockClaimName)
+			// provisioners name volumes after the uid of their claim
+			volumeName = fmt.Sprintf("pvc-%s", ref.UID)
+			claim, volume = ref, phantomReference("PersistentVolume", "v1", "", volumeName)
+		} else {
+			createdClaim, err := vg.clientSet.CoreV1().PersistentVolumeClaims(defaultNamespace).Create(vg.mock.persistentVolumeClaim(mockClaimName))
+			if err != nil {
+				fmt.Printf("Failed to create persistent volume claim because of %v\n", err)
+				continue
+			}
+			volumeName = fmt.Sprintf("pvc-%s", createdClaim.UID)
+			createdVolume, err := vg.clientSet.CoreV1().PersistentVolumes().Create(vg.mock.persistentVolume(volumeName))
+			if err != nil {
+				fmt.Printf("Failed to create persistent volume because of %v\n", err)
+				continue
+			}
+			claim, volume = createdClaim, createdVolume
+		}
+
+		for _, e := range claimEvents(mockClaimName, volumeName) {
+			vg.recorder.Event(claim, e.Type, e.Reason, e.Message)
+			pace()
+		}
+		for _, e := range volumeEvents(volumeName) {
+			vg.recorder.Event(volume, e.Type, e.Reason, e.Message)
+			pace()
+		}
+	}
+	fmt.Printf("Create %d persistent volume claims and volumes successfully.\n", vg.seed)
+}
+
+// finalize all claims and volumes mocked
+func (vg *VolumeGenerator) finalize() {
+	if vg.mock.Phantom {
+		return
+	}
+	options := metav1.ListOptions{
+		LabelSelector: runSelector(vg.mock.RunID),
+	}
+	if err := vg.clientSet.CoreV1().PersistentVolumeClaims(defaultNamespace).DeleteCollection(deleteOptions(), options); err != nil {
+		fmt.Printf("Failed to delete mock persistent volume claims,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock persistent volume claims successfully.\n")
+	}
+	if err := vg.clientSet.CoreV1().PersistentVolumes().DeleteCollection(deleteOptions(), options); err != nil {
+		fmt.Printf("Failed to delete mock persistent volumes,because of %v\n", err)
+	} else {
+		fmt.Print("Delete mock persistent volumes successfully.\n")
+	}
+}
+
+// NewVolumeGenerator return new persistent volume generator instance
+func NewVolumeGenerator(clientSet kubernetes.Interface, recorder record.EventRecorder, seed int, mock *MockOptions) *VolumeGenerator {
+	if seed <= minSeed {
+		seed = minSeed
+	}
+	return &VolumeGenerator{
+		clientSet: clientSet,
+		seed:      seed,
+		recorder:  recorder,
+		mock:      mock,
+	}
+}